@@ -1,10 +1,10 @@
 package webox
 
 import (
+	"context"
 	"crypto/md5"
 	"errors"
 	"fmt"
-	"log"
 	"strings"
 	"time"
 	"webox/api"
@@ -18,11 +18,26 @@ type AccessToken struct {
 	remoteURL string
 	tokenKey  string
 	tokenURL  string
+	locker    cache.Locker
+	provider  TokenProvider
+	cacheScope
 }
 
 /*AccessTokenSafeSeconds token安全时间 */
 const AccessTokenSafeSeconds = 500
 
+/*AccessTokenDefaultExpiresIn 未返回有效期时token的默认有效期(秒) */
+const AccessTokenDefaultExpiresIn = 7200
+
+/*AccessTokenLockTTL 刷新token时持有锁的最长时间 */
+const AccessTokenLockTTL = 30 * time.Second
+
+// tokenFlight 合并进程内对同一token的并发刷新
+var tokenFlight util.SingleFlight
+
+// defaultTokenLocker 未设置AccessTokenLocker时使用的进程内锁
+var defaultTokenLocker cache.Locker = cache.NewMemoryLocker()
+
 // RemoteURL ...
 func (obj *AccessToken) RemoteURL() string {
 	if obj != nil && obj.remoteURL != "" {
@@ -61,20 +76,18 @@ func (obj *AccessToken) parse(options ...AccessTokenOption) {
 }
 
 /*Refresh 刷新AccessToken */
-func (obj *AccessToken) Refresh() *AccessToken {
-
-	obj.getToken(true)
-	return obj
+func (obj *AccessToken) Refresh() error {
+	_, e := obj.getToken(true)
+	return e
 }
 
 /*GetRefreshToken 获取刷新token */
-func (obj *AccessToken) GetRefreshToken() *Token {
-
+func (obj *AccessToken) GetRefreshToken() (*Token, error) {
 	return obj.getToken(true)
 }
 
-/*GetToken 获取token */
-func (obj *AccessToken) GetToken() *Token {
+/*GetToken 获取token，失败时返回的错误可通过errors.As取得*ErrRes以判断微信错误码 */
+func (obj *AccessToken) GetToken() (*Token, error) {
 	return obj.getToken(false)
 }
 
@@ -83,78 +96,151 @@ func (obj *AccessToken) KeyMap() util.Map {
 	return MustKeyMap(obj)
 }
 
-func (obj *AccessToken) getToken(refresh bool) *Token {
+func (obj *AccessToken) getToken(refresh bool) (*Token, error) {
 	key := obj.getCacheKey()
+	token := obj.cachedToken(key)
+	if token != nil && !refresh {
+		return token, nil
+	}
 
-	if !refresh && cache.Has(key) {
-		if v, b := cache.Get(key).(string); b {
-			token, e := ParseToken(v)
-			if e != nil {
-				log.Println("parse token error")
-				return nil
-			}
+	var stale string
+	if token != nil {
+		stale = token.AccessToken
+	}
+	return obj.renewToken(context.Background(), refresh, stale)
+}
 
-			return token
+// renewToken 请求新token，force时若缓存中的token已不是调用方认定失效的stale，说明已被其他协程或进程刷新，直接使用
+func (obj *AccessToken) renewToken(ctx context.Context, force bool, stale string) (*Token, error) {
+	return obj.renewTokenUnless(ctx, force, func(current *Token) bool {
+		return !force || current.AccessToken != stale
+	})
+}
+
+// renewTokenUnless 在进程内单飞及锁的保护下请求新token
+// 拿到锁后若缓存中的token满足fresh，说明无需刷新或已被其他协程或进程刷新，直接使用，避免重复刷新使彼此的token失效；
+// 请求使用发起者的ctx，ctx结束时合并等待的调用方同样得到错误
+func (obj *AccessToken) renewTokenUnless(parent context.Context, force bool, fresh func(current *Token) bool) (*Token, error) {
+	key := obj.getCacheKey()
+	v, e, _ := tokenFlight.Do(key, func() (any, error) {
+		ctx, cancel := contextWithTimeout(parent)
+		defer cancel()
+		unlock, e := obj.getLocker().Lock(ctx, key+".lock", AccessTokenLockTTL)
+		if e != nil {
+			return nil, fmt.Errorf("lock access token: %w", e)
+		}
+		defer unlock()
+
+		if current := obj.cachedToken(key); current != nil && fresh(current) {
+			return current, nil
 		}
-	}
 
-	token, e := requestToken(obj.TokenURL(), obj.AccessTokenProperty)
+		token, e := obj.Provider().RequestToken(ctx, force)
+		if e != nil {
+			return nil, e
+		}
+		if token.ExpiresIn <= 0 {
+			token.ExpiresIn = AccessTokenDefaultExpiresIn
+		}
+		token.Expiry = time.Now().Add(time.Duration(token.ExpiresIn) * time.Second)
+		obj.setToken(token)
+		return token, nil
+	})
 	if e != nil {
-		log.Println(e)
-		return nil
+		return nil, e
 	}
+	return v.(*Token), nil
+}
 
-	if v := token.ExpiresIn; v != 0 {
-		obj.SetTokenWithLife(token.ToJSON(), v-AccessTokenSafeSeconds)
-	} else {
-		obj.SetToken(token.ToJSON())
+// cachedToken 读取缓存中未过期的token
+// 缓存值为*Token，外部缓存无法保存类型时也可以是Token的JSON
+func (obj *AccessToken) cachedToken(key string) *Token {
+	var token *Token
+	switch v := obj.getCache().Get(key).(type) {
+	case *Token:
+		token = v
+	case string:
+		t, e := ParseToken(v)
+		if e != nil {
+			return nil
+		}
+		token = t
+	default:
+		return nil
+	}
+	if token.AccessToken == "" || token.Expired() {
+		return nil
 	}
 	return token
 }
 
-func requestToken(url string, credentials *AccessTokenProperty) (*Token, error) {
-	var t Token
-	var e error
-	token := Get(url, credentials.ToMap())
-	if e := token.Error(); e != nil {
-		return nil, e
+func (obj *AccessToken) getLocker() cache.Locker {
+	if obj.locker != nil {
+		return obj.locker
 	}
-	e = token.Unmarshal(&t)
-	if e != nil {
-		return nil, e
+	return defaultTokenLocker
+}
+
+// Provider 获取token的来源，未设置AccessTokenProvider时使用TokenURL获取
+func (obj *AccessToken) Provider() TokenProvider {
+	if obj.provider != nil {
+		return obj.provider
 	}
-	return &t, nil
+	return NewClassicTokenProvider(obj.TokenURL(), obj.AccessTokenProperty)
 }
 
-/*SetTokenWithLife set string AccessToken with life time */
+/*SetTokenWithLife set string AccessToken with life time in seconds */
 func (obj *AccessToken) SetTokenWithLife(token string, tts int64) *AccessToken {
-	return obj.setToken(token, tts)
+	return obj.setToken(&Token{
+		AccessToken: token,
+		ExpiresIn:   tts,
+		Expiry:      time.Now().Add(time.Duration(tts) * time.Second),
+	})
 }
 
 /*SetToken set string AccessToken */
 func (obj *AccessToken) SetToken(token string) *AccessToken {
-	return obj.setToken(token, 7200-AccessTokenSafeSeconds)
+	return obj.SetTokenWithLife(token, AccessTokenDefaultExpiresIn)
 }
 
-func (obj *AccessToken) setToken(token string, tts int64) *AccessToken {
-	cache.Set(obj.getCacheKey(), token, time.Duration(tts*int64(time.Second)))
+// setToken 缓存token，缓存时间比有效期少AccessTokenSafeSeconds
+func (obj *AccessToken) setToken(token *Token) *AccessToken {
+	ttl := token.Remaining() - AccessTokenSafeSeconds*time.Second
+	if ttl <= 0 {
+		ttl = token.Remaining()
+	}
+	if ttl > 0 {
+		obj.getCache().Set(obj.getCacheKey(), token, ttl)
+	}
 	return obj
 }
 
 func (obj *AccessToken) getCredentials() string {
-	cred := strings.Join([]string{obj.GrantType, obj.AppID, obj.AppSecret}, ".")
+	return credentialsKey(obj.AccessTokenProperty)
+}
+
+// credentialsKey 由grant_type、appid及secret计算的摘要，secret不同的配置分别缓存token
+func credentialsKey(property *AccessTokenProperty) string {
+	if property == nil {
+		property = &AccessTokenProperty{}
+	}
+	cred := strings.Join([]string{property.GrantType, property.AppID, property.AppSecret}, ".")
 	c := md5.Sum([]byte(cred))
 	return fmt.Sprintf("%x", c[:])
 }
 
 func (obj *AccessToken) getCacheKey() string {
-	return "webox.access_token." + obj.getCredentials()
+	if obj.provider != nil {
+		c := md5.Sum([]byte(obj.provider.Key()))
+		return obj.cacheKey("access_token", fmt.Sprintf("%x", c[:]))
+	}
+	return obj.cacheKey("access_token", obj.getCredentials())
 }
 
 const accessTokenNil = "nil point AccessToken"
 const tokenNil = "nil point token"
 
-/*MustKeyMap get AccessToken's key,value with map when nil or error return nil map */
+/*MustKeyMap get AccessToken's key,value with map when nil or error return empty map */
 func MustKeyMap(at *AccessToken) util.Map {
 	if m, e := KeyMap(at); e == nil {
 		return m
@@ -167,8 +253,12 @@ func KeyMap(at *AccessToken) (util.Map, error) {
 	if at == nil {
 		return nil, errors.New(accessTokenNil)
 	}
-	if token := at.GetToken(); token != nil {
-		return token.KeyMap(), nil
+	token, e := at.GetToken()
+	if e != nil {
+		return nil, e
+	}
+	if m := token.KeyMap(); m != nil {
+		return m, nil
 	}
 	return nil, errors.New(tokenNil)
 }
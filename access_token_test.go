@@ -0,0 +1,227 @@
+package webox
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+	"webox/api"
+	"webox/cache"
+	"webox/util"
+)
+
+// TestAccessToken_ConcurrentRefresh 并发获取及刷新token时只请求一次
+func TestAccessToken_ConcurrentRefresh(t *testing.T) {
+	var hits int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&hits, 1)
+		time.Sleep(50 * time.Millisecond)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"access_token":"token` + string(rune('0'+n)) + `","expires_in":7200}`))
+	}))
+	defer srv.Close()
+
+	property := &AccessTokenProperty{GrantType: GrantTypeClient, AppID: "wx" + strconv.FormatInt(time.Now().UnixNano(), 10), AppSecret: "secret"}
+	// 两个实例模拟共享缓存的两个进程
+	tokens := []*AccessToken{NewAccessToken(property, AccessTokenRemote(srv.URL)), NewAccessToken(property, AccessTokenRemote(srv.URL))}
+
+	run := func(get func(at *AccessToken) (*Token, error)) []string {
+		var wg sync.WaitGroup
+		results := make([]string, 20)
+		for i := range results {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				if token, e := get(tokens[i%2]); e == nil {
+					results[i] = token.AccessToken
+				}
+			}(i)
+		}
+		wg.Wait()
+		return results
+	}
+
+	for _, v := range run((*AccessToken).GetToken) {
+		if v != "token1" {
+			t.Fatalf("got token %q, want token1", v)
+		}
+	}
+	if n := atomic.LoadInt32(&hits); n != 1 {
+		t.Fatalf("got %d token requests, want 1", n)
+	}
+
+	for _, v := range run((*AccessToken).GetRefreshToken) {
+		if v != "token2" {
+			t.Fatalf("got refreshed token %q, want token2", v)
+		}
+	}
+	if n := atomic.LoadInt32(&hits); n != 2 {
+		t.Fatalf("got %d token requests after refresh, want 2", n)
+	}
+}
+
+// TestClient_ReplayOnInvalidToken token失效时刷新并重放请求
+func TestClient_ReplayOnInvalidToken(t *testing.T) {
+	var tokenHits, apiHits int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == api.AccessToken {
+			n := atomic.AddInt32(&tokenHits, 1)
+			_, _ = w.Write([]byte(`{"access_token":"token` + strconv.Itoa(int(n)) + `","expires_in":7200}`))
+			return
+		}
+		atomic.AddInt32(&apiHits, 1)
+		if r.URL.Query().Get(api.AccessTokenKey) == "token1" {
+			_, _ = w.Write([]byte(`{"errcode":40001,"errmsg":"invalid credential"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"errcode":0,"errmsg":"ok"}`))
+	}))
+	defer srv.Close()
+
+	property := &AccessTokenProperty{GrantType: GrantTypeClient, AppID: "wx" + strconv.FormatInt(time.Now().UnixNano(), 10), AppSecret: "secret"}
+	client := NewClient(ClientBodyType(BodyTypeJSON), ClientAccessToken(NewAccessToken(property, AccessTokenRemote(srv.URL))))
+	if e := client.Post(context.Background(), srv.URL+"/api", nil, util.Map{}).Error(); e != nil {
+		t.Fatal(e)
+	}
+	if tokenHits != 2 || apiHits != 2 {
+		t.Fatalf("got %d token and %d api requests, want 2 and 2", tokenHits, apiHits)
+	}
+}
+
+// TestAccessToken_GetToken 返回微信错误码，成功后记录过期时间
+func TestAccessToken_GetToken(t *testing.T) {
+	var fail atomic.Bool
+	fail.Store(true)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if fail.Load() {
+			_, _ = w.Write([]byte(`{"errcode":40125,"errmsg":"invalid appsecret"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"access_token":"token","expires_in":7200}`))
+	}))
+	defer srv.Close()
+
+	property := &AccessTokenProperty{GrantType: GrantTypeClient, AppID: "wx" + strconv.FormatInt(time.Now().UnixNano(), 10), AppSecret: "secret"}
+	at := NewAccessToken(property, AccessTokenRemote(srv.URL))
+	var errRes *ErrRes
+	if _, e := at.GetToken(); !errors.As(e, &errRes) || errRes.ErrCode != 40125 {
+		t.Fatalf("expected errcode 40125, got %v", e)
+	}
+	if resp := NewClient(ClientAccessToken(at)).Post(context.Background(), srv.URL, nil, nil); resp.Error() == nil {
+		t.Fatal("expected request error when token is unavailable")
+	}
+
+	fail.Store(false)
+	token, e := at.GetToken()
+	if e != nil {
+		t.Fatal(e)
+	}
+	if remaining := token.Remaining(); remaining > 7200*time.Second || remaining < 7190*time.Second || token.Expired() {
+		t.Fatalf("unexpected remaining lifetime %v", remaining)
+	}
+	if cached, _ := at.GetToken(); cached != token {
+		t.Fatal("expected cached token")
+	}
+}
+
+// TestAccessTokenCache 不同缓存实例及命名空间的token互不影响
+func TestAccessTokenCache(t *testing.T) {
+	property := &AccessTokenProperty{GrantType: GrantTypeClient, AppID: "wx", AppSecret: "secret"}
+	c := cache.NewMapCache()
+	tenant1 := NewAccessToken(property, AccessTokenCache(c, "tenant1")).SetToken("token1")
+	tenant2 := NewAccessToken(property, AccessTokenCache(c, "tenant2")).SetToken("token2")
+	other := NewAccessToken(property, AccessTokenCache(cache.NewMapCache(), "tenant1"))
+
+	if !c.Has("tenant1.access_token."+tenant1.getCredentials()) || cache.Has(tenant1.getCacheKey()) {
+		t.Fatal("token should be stored only in the instance cache")
+	}
+	for at, want := range map[*AccessToken]string{tenant1: "token1", tenant2: "token2"} {
+		if token, e := at.GetToken(); e != nil || token.AccessToken != want {
+			t.Fatalf("got %v %v, want %s", token, e, want)
+		}
+	}
+	if token := other.cachedToken(other.getCacheKey()); token != nil {
+		t.Fatalf("unexpected token %v from another cache instance", token)
+	}
+}
+
+// TestJSSDKCache_AccessTokenProperty 由property创建的token使用JSSDK的缓存，与选项顺序无关
+func TestJSSDKCache_AccessTokenProperty(t *testing.T) {
+	property := &AccessTokenProperty{GrantType: GrantTypeClient, AppID: "wx", AppSecret: "secret"}
+	c := cache.NewMapCache()
+	jssdk := NewJSSDK(&JSSDKProperty{AppID: "wx"}, JSSDKAccessTokenProperty(property), JSSDKCache(c, "tenant1"))
+	jssdk.AccessToken.SetToken("token1")
+	if !c.Has("tenant1.access_token."+jssdk.AccessToken.getCredentials()) || cache.Has(jssdk.AccessToken.getCacheKey()) {
+		t.Fatal("token should be stored in the jssdk cache")
+	}
+
+	own := cache.NewMapCache()
+	jssdk = NewJSSDK(&JSSDKProperty{AppID: "wx"}, JSSDKCache(c, "tenant1"),
+		JSSDKAccessToken(NewAccessToken(property, AccessTokenCache(own, "tenant2"))))
+	if jssdk.AccessToken.cache != own || jssdk.AccessToken.getCacheKey() != "tenant2.access_token."+jssdk.AccessToken.getCredentials() {
+		t.Fatal("token with its own cache should keep it")
+	}
+}
+
+// TestOfficialAccountCache 公众号的缓存作为token、JSSDK及二维码归因的默认缓存
+func TestOfficialAccountCache(t *testing.T) {
+	c := cache.NewMapCache()
+	jssdk := NewJSSDK(&JSSDKProperty{AppID: "wx"}, JSSDKTokenProvider(NewStaticTokenProvider("token")))
+	oa := NewOfficialAccount(&OfficialAccountProperty{AppID: "wx", AppSecret: "secret"},
+		OfficialAccountCache(c, "tenant1"),
+		OfficialAccountAccessTokenProperty(&AccessTokenProperty{GrantType: GrantTypeClient, AppID: "wx", AppSecret: "secret"}),
+		OfficialAccountJSSDK(jssdk),
+	)
+	for name, scope := range map[string]*cacheScope{
+		"access_token":       &oa.AccessToken.cacheScope,
+		"jssdk":              &jssdk.cacheScope,
+		"jssdk access_token": &jssdk.AccessToken.cacheScope,
+		"qrcode":             &oa.QrCodeAttribution().cacheScope,
+	} {
+		if scope.cache != c || scope.namespace != "tenant1" {
+			t.Fatalf("%s does not use the official account cache", name)
+		}
+	}
+	if attribution := oa.QrCodeAttribution(QrCodeAttributionCache(nil, "tenant2")); attribution.sceneKey("s") != "tenant2.qrcode.scene.wx.s" {
+		t.Fatalf("unexpected scene key %s", attribution.sceneKey("s"))
+	}
+}
+
+// ttlCache 记录Set的缓存时间
+type ttlCache struct {
+	*cache.MapCache
+	ttl map[string]time.Duration
+}
+
+func (c *ttlCache) Set(key string, val any, ttl time.Duration) {
+	c.ttl[key] = ttl
+	c.MapCache.Set(key, val, ttl)
+}
+
+// TestJSSDK_SetTicket ticket的缓存时间与token相同地预留安全时间，有效期缺失或过短时不会缓存为永不过期
+func TestJSSDK_SetTicket(t *testing.T) {
+	c := &ttlCache{MapCache: cache.NewMapCache(), ttl: map[string]time.Duration{}}
+	jssdk := NewJSSDK(&JSSDKProperty{AppID: "wx"}, JSSDKCache(c, "tenant1"))
+
+	for expiresIn, want := range map[int64]time.Duration{
+		7200: (7200 - AccessTokenSafeSeconds) * time.Second,
+		0:    (TicketDefaultExpiresIn - AccessTokenSafeSeconds) * time.Second,
+		300:  300 * time.Second,
+	} {
+		key := jssdk.getCacheKey(strconv.FormatInt(expiresIn, 10))
+		jssdk.setTicket(key, &TicketRes{Ticket: "ticket", ExpiresIn: expiresIn})
+		if got := c.ttl[key]; got > want || got < want-time.Second {
+			t.Fatalf("expires_in %d: got ttl %v, want %v", expiresIn, got, want)
+		}
+		if tr := jssdk.cachedTicket(key); tr == nil || tr.Remaining() <= 0 {
+			t.Fatalf("expires_in %d: ticket should be cached", expiresIn)
+		}
+	}
+}
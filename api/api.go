@@ -15,6 +15,7 @@ const APIMCHHK = "https://apihk.mch.weixin.qq.com"
 const APIMCHDefault = "https://api.mch.weixin.qq.com"
 
 const ApiWeixin = "https://api.weixin.qq.com"
+const MpWeixin = "https://mp.weixin.qq.com"
 const Oauth2Authorize = "https://open.weixin.qq.com/connect/oauth2/authorize"
 const Oauth2AccessToken = "https://api.weixin.qq.com/sns/oauth2/access_token"
 const SnsUserinfo = "https://api.weixin.qq.com/sns/userinfo"
@@ -73,8 +74,23 @@ const DefaultTimeout = 30
 /*AccessTokenKey 键值 */
 const AccessTokenKey = "access_token"
 const AccessToken = "/cgi-bin/token"
+const StableAccessToken = "/cgi-bin/stable_token"
 
 const GetKFList = "/cgi-bin/customservice/getkflist"
+const GetOnlineKFList = "/cgi-bin/customservice/getonlinekflist"
+const KfAccountAdd = "/customservice/kfaccount/add"
+const KfAccountUpdate = "/customservice/kfaccount/update"
+const KfAccountDel = "/customservice/kfaccount/del"
+const KfAccountUploadHeadImg = "/customservice/kfaccount/uploadheadimg"
+const KfAccountInviteWorker = "/customservice/kfaccount/inviteworker"
+const KfSessionCreate = "/customservice/kfsession/create"
+const KfSessionClose = "/customservice/kfsession/close"
+const KfSessionGet = "/customservice/kfsession/getsession"
+const KfSessionGetList = "/customservice/kfsession/getsessionlist"
+const KfSessionGetWaitCase = "/customservice/kfsession/getwaitcase"
+const KfMsgRecordList = "/customservice/msgrecord/getmsglist"
+const SendMessageCustom = "/cgi-bin/message/custom/send"
+const MessageCustomTyping = "/cgi-bin/message/custom/typing"
 
 const MenuCreate = "/cgi-bin/menu/create"
 const GetMenu = "/cgi-bin/menu/get"
@@ -90,6 +106,14 @@ const GetAllPrivateTemplate = "/cgi-bin/template/get_all_private_template"
 const DelPrivateTemplate = "/cgi-bin/template/del_private_template"
 const SendMessageTemplate = "/cgi-bin/message/template/send"
 
+const SubscribeAddTemplate = "/wxaapi/newtmpl/addtemplate"
+const SubscribeDelTemplate = "/wxaapi/newtmpl/deltemplate"
+const SubscribeGetCategory = "/wxaapi/newtmpl/getcategory"
+const SubscribeGetPubTemplateKeywords = "/wxaapi/newtmpl/getpubtemplatekeywords"
+const SubscribeGetPubTemplateTitles = "/wxaapi/newtmpl/getpubtemplatetitles"
+const SubscribeGetTemplate = "/wxaapi/newtmpl/gettemplate"
+const SendMessageSubscribe = "/cgi-bin/message/subscribe/bizsend"
+
 const UploadMedia = "/cgi-bin/media/upload"
 const UploadImg = "/cgi-bin/media/uploadimg"
 const GetMedia = "/cgi-bin/media/get"
@@ -121,6 +145,8 @@ const MessageMassSendall = "/cgi-bin/message/mass/sendall"
 const MessageMassPreview = "cgi-bin/message/mass/preview"
 const DeleteMessageMass = "/cgi-bin/message/mass/delete"
 const GetMessageMass = "/cgi-bin/message/mass/get"
+const MessageMassSpeedGet = "/cgi-bin/message/mass/speed/get"
+const MessageMassSpeedSet = "/cgi-bin/message/mass/speed/set"
 
 // DatacubeTimeLayout time format for datacube
 const DatacubeTimeLayout = "2006-01-02"
@@ -154,6 +180,17 @@ const DelMaterial = "/cgi-bin/material/del_material"
 const DelNews = "/cgi-bin/material/update_news"
 const GetMaterialcount = "/cgi-bin/material/get_materialcount"
 const ListMaterial = "/cgi-bin/material/batchget_material"
+const DraftAdd = "/cgi-bin/draft/add"
+const DraftGet = "/cgi-bin/draft/get"
+const DraftDelete = "/cgi-bin/draft/delete"
+const DraftUpdate = "/cgi-bin/draft/update"
+const DraftCount = "/cgi-bin/draft/count"
+const DraftBatchGet = "/cgi-bin/draft/batchget"
+const FreePublishSubmit = "/cgi-bin/freepublish/submit"
+const FreePublishGet = "/cgi-bin/freepublish/get"
+const FreePublishDelete = "/cgi-bin/freepublish/delete"
+const FreePublishGetArticle = "/cgi-bin/freepublish/getarticle"
+const FreePublishBatchGet = "/cgi-bin/freepublish/batchget"
 const OpenComment = "/cgi-bin/comment/open"
 const CloseComment = "/cgi-bin/comment/close"
 const ListComment = "/cgi-bin/comment/list"
@@ -176,6 +213,10 @@ const CountDepositCardCode = "/card/code/getdepositcount"
 const CreateCardQrcode = "/card/qrcode/create"
 const CheckCardCode = "/card/code/checkcode"
 const GetCardCode = "/card/code/get"
+const ConsumeCardCode = "/card/code/consume"
+const DecryptCardCode = "/card/code/decrypt"
+const MarkCardCode = "/card/code/mark"
+const UnavailableCardCode = "/card/code/unavailable"
 const GetCardMPNewsHTML = "/card/mpnews/gethtml"
 const SetCardTestWhiteList = "/card/testwhitelist/set"
 const CreateCard = "/card/create"
@@ -190,6 +231,12 @@ const GetUserCardlist = "/card/user/getcardlist"
 const SetCardPayCell = "card/paycell/set"
 const ModifyCardStock = "card/modifystock"
 const CheckinCardBoardingpass = "/card/boardingpass/checkin"
+const MemberCardActivate = "/card/membercard/activate"
+const MemberCardActivateUserFormSet = "/card/membercard/activateuserform/set"
+const MemberCardActivateGetURL = "/card/membercard/activate/geturl"
+const MemberCardActivateTempInfoGet = "/card/membercard/activatetempinfo/get"
+const MemberCardUserInfoGet = "/card/membercard/userinfo/get"
+const MemberCardUpdateUser = "/card/membercard/updateuser"
 const AddPoi = "/cgi-bin/poi/addpoi"
 const PoiGetPoi = "/cgi-bin/poi/getpoi"
 const UpdatePoi = "/cgi-bin/poi/updatepoi"
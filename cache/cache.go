@@ -7,7 +7,7 @@ type Cache interface {
 	Add(key string, val any)
 	Set(key string, val any, timeout time.Duration)
 	Get(key string) any
-	Delete(prefix string)
+	Delete(key string)
 	Clear()
 	Has(key string) bool
 }
@@ -16,12 +16,35 @@ type Cache interface {
 var cache Cache
 
 func init() {
-	RegisterCache(NewMapCache())
+	RegisterCache(NewMapCache(MapCacheJanitor(time.Minute)))
 }
 
+// store the default cache as a Store
+var store Store
+
 /*RegisterCache register cache to map */
 func RegisterCache(c Cache) {
 	cache = c
+	if a, b := c.(*CacheAdapter); b {
+		store = a.Store()
+	} else {
+		store = NewStoreAdapter(c)
+	}
+}
+
+/*RegisterStore register a Store as the default cache, package-level functions use it through CacheAdapter */
+func RegisterStore(s Store) {
+	RegisterCache(NewCacheAdapter(s, 5*time.Second))
+}
+
+/*Default get the default cache */
+func Default() Cache {
+	return cache
+}
+
+/*DefaultStore get the default cache as a Store */
+func DefaultStore() Store {
+	return store
 }
 
 // Get get value
@@ -44,6 +67,18 @@ func Delete(key string) {
 	cache.Delete(key)
 }
 
+/*PrefixDeleter implemented by caches that can delete values by key prefix */
+type PrefixDeleter interface {
+	DeleteByPrefix(prefix string)
+}
+
+// DeleteByPrefix delete values whose key has the prefix, do nothing when the cache is not a PrefixDeleter
+func DeleteByPrefix(prefix string) {
+	if d, b := cache.(PrefixDeleter); b {
+		d.DeleteByPrefix(prefix)
+	}
+}
+
 // Clear clear all
 func Clear() {
 	cache.Clear()
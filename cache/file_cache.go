@@ -0,0 +1,234 @@
+package cache
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// FileCache 持久化到本地文件的缓存，适用于命令行工具及单机部署
+// 每次写入以临时文件加rename的方式原子替换，同一主机上的多个进程通过文件锁互斥；
+// string及[]byte原样保存，其他值保存为JSON，Get返回string。文件损坏时备份为.corrupt后重新开始
+type FileCache struct {
+	path    string
+	mu      sync.Mutex
+	lock    *os.File
+	entries map[string]fileEntry
+	info    fs.FileInfo // 最近一次读取或写入后的文件信息，用于判断是否被其他进程修改
+}
+
+type fileEntry struct {
+	Value  string `json:"v"`
+	Expiry int64  `json:"e,omitempty"` // 过期时间(UnixNano)，为0时不过期
+}
+
+func (e fileEntry) expired(now time.Time) bool {
+	return e.Expiry != 0 && e.Expiry < now.UnixNano()
+}
+
+type fileContent struct {
+	Version int                  `json:"version"`
+	Entries map[string]fileEntry `json:"entries"`
+}
+
+const fileCacheVersion = 1
+
+// NewFileCache 目录不存在时自动创建
+func NewFileCache(path string) (*FileCache, error) {
+	if e := os.MkdirAll(filepath.Dir(path), 0o700); e != nil {
+		return nil, e
+	}
+	lock, e := os.OpenFile(path+".lock", os.O_CREATE|os.O_RDWR, 0o600)
+	if e != nil {
+		return nil, e
+	}
+	c := &FileCache{
+		path:    path,
+		lock:    lock,
+		entries: make(map[string]fileEntry),
+	}
+	if e = c.withLock(false, func() error { return nil }); e != nil {
+		_ = lock.Close()
+		return nil, e
+	}
+	return c, nil
+}
+
+// Close 释放文件锁句柄，数据在每次写入时已保存
+func (c *FileCache) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lock.Close()
+}
+
+/*Add set value without ttl */
+func (c *FileCache) Add(key string, val any) {
+	c.Set(key, val, 0)
+}
+
+/*Set set value with ttl */
+func (c *FileCache) Set(key string, val any, ttl time.Duration) {
+	v, e := encodeValue(val)
+	if e != nil {
+		log.Println(e)
+		return
+	}
+	entry := fileEntry{Value: string(v)}
+	if ttl != 0 {
+		entry.Expiry = time.Now().Add(ttl).UnixNano()
+	}
+	c.update(func(entries map[string]fileEntry) {
+		entries[key] = entry
+	})
+}
+
+/*Get get value as string, nil when not exist or expired */
+func (c *FileCache) Get(key string) any {
+	var v any
+	e := c.withLock(false, func() error {
+		if entry, b := c.entries[key]; b && !entry.expired(time.Now()) {
+			v = entry.Value
+		}
+		return nil
+	})
+	if e != nil {
+		log.Println(e)
+	}
+	return v
+}
+
+/*Has check exist */
+func (c *FileCache) Has(key string) bool {
+	return c.Get(key) != nil
+}
+
+/*Delete one value */
+func (c *FileCache) Delete(key string) {
+	c.update(func(entries map[string]fileEntry) {
+		delete(entries, key)
+	})
+}
+
+/*DeleteByPrefix delete values whose key has the prefix */
+func (c *FileCache) DeleteByPrefix(prefix string) {
+	c.update(func(entries map[string]fileEntry) {
+		for key := range entries {
+			if strings.HasPrefix(key, prefix) {
+				delete(entries, key)
+			}
+		}
+	})
+}
+
+/*Clear delete all values */
+func (c *FileCache) Clear() {
+	c.update(func(entries map[string]fileEntry) {
+		clear(entries)
+	})
+}
+
+// update 在排他锁内重新加载文件、修改并写回
+func (c *FileCache) update(fn func(entries map[string]fileEntry)) {
+	e := c.withLock(true, func() error {
+		fn(c.entries)
+		return c.save()
+	})
+	if e != nil {
+		log.Println(e)
+	}
+}
+
+// withLock 持有文件锁执行fn，文件被其他进程修改过时先重新加载
+func (c *FileCache) withLock(exclusive bool, fn func() error) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e := lockFile(c.lock, exclusive); e != nil {
+		return fmt.Errorf("lock cache file: %w", e)
+	}
+	defer func() { _ = unlockFile(c.lock) }()
+
+	if e := c.reload(); e != nil {
+		return e
+	}
+	return fn()
+}
+
+// reload 文件被替换或修改过时重新读取，无法解析时备份后清空
+func (c *FileCache) reload() error {
+	info, e := os.Stat(c.path)
+	if errors.Is(e, fs.ErrNotExist) {
+		c.entries = make(map[string]fileEntry)
+		c.info = nil
+		return nil
+	}
+	if e != nil {
+		return e
+	}
+	if c.info != nil && os.SameFile(c.info, info) && info.ModTime().Equal(c.info.ModTime()) && info.Size() == c.info.Size() {
+		return nil
+	}
+
+	b, e := os.ReadFile(c.path)
+	if e != nil {
+		return e
+	}
+	var content fileContent
+	if e = jsoniter.Unmarshal(b, &content); e != nil || content.Version != fileCacheVersion {
+		log.Println("cache file corrupted, reset:", c.path, e)
+		_ = os.Rename(c.path, c.path+".corrupt")
+		content.Entries = nil
+	}
+	c.entries = content.Entries
+	if c.entries == nil {
+		c.entries = make(map[string]fileEntry)
+	}
+	c.info = info
+	return nil
+}
+
+// save 清理过期条目后写入临时文件并rename替换
+func (c *FileCache) save() error {
+	now := time.Now()
+	for key, entry := range c.entries {
+		if entry.expired(now) {
+			delete(c.entries, key)
+		}
+	}
+	b, e := jsoniter.Marshal(&fileContent{Version: fileCacheVersion, Entries: c.entries})
+	if e != nil {
+		return e
+	}
+
+	tmp, e := os.CreateTemp(filepath.Dir(c.path), filepath.Base(c.path)+".tmp*")
+	if e != nil {
+		return e
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+	if _, e = tmp.Write(b); e == nil {
+		e = tmp.Sync()
+	}
+	if ce := tmp.Close(); e == nil {
+		e = ce
+	}
+	if e != nil {
+		return e
+	}
+	if e = os.Rename(tmp.Name(), c.path); e != nil {
+		return e
+	}
+
+	info, e := os.Stat(c.path)
+	if e != nil {
+		return e
+	}
+	c.info = info
+	return nil
+}
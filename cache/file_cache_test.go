@@ -0,0 +1,67 @@
+package cache_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"webox/cache"
+)
+
+// TestFileCache 重新打开后保留数据，多个实例共享同一文件
+func TestFileCache(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache", "webox.json")
+	c1, err := cache.NewFileCache(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c1.Close()
+	c2, err := cache.NewFileCache(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c2.Close()
+
+	c1.Set("webox.jssdk.ticket", "ticket", time.Hour)
+	c1.Set("webox.token", map[string]any{"access_token": "token"}, time.Hour)
+	c1.Set("webox.expired", "x", time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+	if c2.Get("webox.jssdk.ticket") != "ticket" || c2.Get("webox.token") != `{"access_token":"token"}` || c2.Has("webox.expired") {
+		t.Fatalf("unexpected values %v %v", c2.Get("webox.jssdk.ticket"), c2.Get("webox.token"))
+	}
+
+	c2.DeleteByPrefix("webox.jssdk.")
+	if c1.Has("webox.jssdk.ticket") || !c1.Has("webox.token") {
+		t.Fatal("DeleteByPrefix should be visible to other instances")
+	}
+
+	c3, err := cache.NewFileCache(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c3.Close()
+	if !c3.Has("webox.token") {
+		t.Fatal("value should survive reopening")
+	}
+}
+
+// TestFileCache_Corrupted 文件损坏时备份后重新开始
+func TestFileCache_Corrupted(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "webox.json")
+	if err := os.WriteFile(path, []byte(`{"version":1,"entries":{`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	c, err := cache.NewFileCache(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	if _, err = os.Stat(path + ".corrupt"); err != nil {
+		t.Fatal("corrupted file should be kept as .corrupt")
+	}
+	c.Set("key", "value", 0)
+	if c.Get("key") != "value" {
+		t.Fatal("cache should work after recovery")
+	}
+}
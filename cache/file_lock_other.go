@@ -0,0 +1,14 @@
+//go:build !unix
+
+package cache
+
+import "os"
+
+// 非unix平台不支持flock，仅保证进程内互斥
+func lockFile(f *os.File, exclusive bool) error {
+	return nil
+}
+
+func unlockFile(f *os.File) error {
+	return nil
+}
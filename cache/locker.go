@@ -0,0 +1,69 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+/*Locker define a lock interface, implement it with a shared store (such as redis) to lock across processes */
+type Locker interface {
+	// Lock 阻塞直到获得key对应的锁或ctx结束，ttl为锁的最长持有时间，防止持有者崩溃后无法释放
+	Lock(ctx context.Context, key string, ttl time.Duration) (unlock func(), e error)
+}
+
+/*MemoryLocker 进程内的Locker实现 */
+type MemoryLocker struct {
+	mu    sync.Mutex
+	locks map[string]*memoryLock
+}
+
+type memoryLock struct {
+	released chan struct{}
+	timer    *time.Timer
+}
+
+// NewMemoryLocker ...
+func NewMemoryLocker() *MemoryLocker {
+	return &MemoryLocker{
+		locks: make(map[string]*memoryLock),
+	}
+}
+
+/*Lock 获取锁，ttl为0时不会自动释放 */
+func (m *MemoryLocker) Lock(ctx context.Context, key string, ttl time.Duration) (unlock func(), e error) {
+	for {
+		m.mu.Lock()
+		l, b := m.locks[key]
+		if !b {
+			l = &memoryLock{released: make(chan struct{})}
+			m.locks[key] = l
+			if ttl > 0 {
+				l.timer = time.AfterFunc(ttl, func() { m.release(key, l) })
+			}
+			m.mu.Unlock()
+			var once sync.Once
+			return func() { once.Do(func() { m.release(key, l) }) }, nil
+		}
+		m.mu.Unlock()
+
+		select {
+		case <-l.released:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+func (m *MemoryLocker) release(key string, l *memoryLock) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.locks[key] != l {
+		return
+	}
+	if l.timer != nil {
+		l.timer.Stop()
+	}
+	delete(m.locks, key)
+	close(l.released)
+}
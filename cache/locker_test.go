@@ -0,0 +1,42 @@
+package cache_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"webox/cache"
+)
+
+// TestMemoryLocker_Lock ...
+func TestMemoryLocker_Lock(t *testing.T) {
+	locker := cache.NewMemoryLocker()
+	unlock, err := locker.Lock(context.Background(), "key", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err = locker.Lock(ctx, "key", 0); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		unlock()
+	}()
+	unlock2, err := locker.Lock(context.Background(), "key", 20*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	unlock() // 重复释放不影响新的持有者
+	ctx, cancel = context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	// 持有者未释放时ttl到期自动释放
+	if _, err = locker.Lock(ctx, "key", 0); err != nil {
+		t.Fatal(err)
+	}
+	unlock2()
+}
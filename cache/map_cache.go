@@ -1,42 +1,104 @@
 package cache
 
 import (
+	"container/list"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
-/*MapCache MapCache */
+/*MapCache 进程内缓存，可设置最大条目数(超出时淘汰最久未使用的条目)及定时清理过期条目 */
 type MapCache struct {
-	cache sync.Map
+	mu         sync.Mutex
+	items      map[string]*list.Element
+	lru        *list.List // 最近使用的条目在前
+	maxEntries int
+	interval   time.Duration
+	stop       chan struct{}
+	stopOnce   sync.Once
+	stats      mapCacheCounter
 }
 
 type cachedData struct {
+	key string
 	val any
 	ttl time.Time
 }
 
+func (d *cachedData) expired(now time.Time) bool {
+	return !d.ttl.IsZero() && d.ttl.Before(now)
+}
+
+type mapCacheCounter struct {
+	hits        atomic.Int64
+	misses      atomic.Int64
+	evictions   atomic.Int64
+	expirations atomic.Int64
+}
+
+/*MapCacheStats MapCache的统计数据 */
+type MapCacheStats struct {
+	Hits        int64 // 命中次数
+	Misses      int64 // 未命中次数(含已过期)
+	Evictions   int64 // 因超出最大条目数被淘汰的条目数
+	Expirations int64 // 过期被清理的条目数
+	Size        int   // 当前条目数
+}
+
+// MapCacheOption ...
+type MapCacheOption func(m *MapCache)
+
+// MapCacheMaxEntries 最大条目数，超出时淘汰最久未使用的条目，为0时不限制
+func MapCacheMaxEntries(n int) MapCacheOption {
+	return func(m *MapCache) {
+		m.maxEntries = n
+	}
+}
+
+// MapCacheJanitor 每隔interval清理一次过期条目，调用Stop结束清理
+func MapCacheJanitor(interval time.Duration) MapCacheOption {
+	return func(m *MapCache) {
+		m.interval = interval
+	}
+}
+
 // NewMapCache ...
-func NewMapCache() *MapCache {
-	return &MapCache{}
+func NewMapCache(options ...MapCacheOption) *MapCache {
+	m := &MapCache{
+		items: make(map[string]*list.Element),
+		lru:   list.New(),
+		stop:  make(chan struct{}),
+	}
+	for _, o := range options {
+		o(m)
+	}
+	if m.interval > 0 {
+		go m.janitor()
+	}
+	return m
 }
 
-/*Set check exist */
+/*Add set value without ttl */
 func (m *MapCache) Add(key string, val any) {
 	m.Set(key, val, 0)
 }
 
-/*GetD get interface with default */
+/*Get get value, nil when not exist or expired */
 func (m *MapCache) Get(key string) any {
-	if v, b := m.cache.Load(key); b {
-		switch vv := v.(type) {
-		case *cachedData:
-			if !vv.ttl.IsZero() && vv.ttl.Before(time.Now()) {
-				m.cache.Delete(key)
-				return nil
-			}
-			return vv.val
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if el, b := m.items[key]; b {
+		data := el.Value.(*cachedData)
+		if !data.expired(time.Now()) {
+			m.lru.MoveToFront(el)
+			m.stats.hits.Add(1)
+			return data.val
 		}
+		m.remove(el)
+		m.stats.expirations.Add(1)
 	}
+	m.stats.misses.Add(1)
 	return nil
 }
 
@@ -46,32 +108,107 @@ func (m *MapCache) Set(key string, val any, duration time.Duration) {
 	if duration != 0 {
 		ttl = time.Now().Add(duration)
 	}
-	m.cache.Store(key, &cachedData{
-		val: val,
-		ttl: ttl,
-	})
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if el, b := m.items[key]; b {
+		el.Value = &cachedData{key: key, val: val, ttl: ttl}
+		m.lru.MoveToFront(el)
+		return
+	}
+	m.items[key] = m.lru.PushFront(&cachedData{key: key, val: val, ttl: ttl})
+	for m.maxEntries > 0 && m.lru.Len() > m.maxEntries {
+		m.remove(m.lru.Back())
+		m.stats.evictions.Add(1)
+	}
 }
 
 /*Has check exist */
 func (m *MapCache) Has(key string) bool {
-	if v, b := m.cache.Load(key); b {
-		switch vv := v.(type) {
-		case *cachedData:
-			if !vv.ttl.IsZero() && vv.ttl.Before(time.Now()) {
-				return false
-			}
-			return true
-		}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if el, b := m.items[key]; b {
+		return !el.Value.(*cachedData).expired(time.Now())
 	}
 	return false
 }
 
 /*Delete one value */
 func (m *MapCache) Delete(key string) {
-	m.Delete(key)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if el, b := m.items[key]; b {
+		m.remove(el)
+	}
+}
+
+/*DeleteByPrefix delete values whose key has the prefix */
+func (m *MapCache) DeleteByPrefix(prefix string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for key, el := range m.items {
+		if strings.HasPrefix(key, prefix) {
+			m.remove(el)
+		}
+	}
 }
 
 /*Clear delete all values */
 func (m *MapCache) Clear() {
-	*m = MapCache{}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.items = make(map[string]*list.Element)
+	m.lru.Init()
+}
+
+/*DeleteExpired delete all expired values */
+func (m *MapCache) DeleteExpired() {
+	now := time.Now()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, el := range m.items {
+		if el.Value.(*cachedData).expired(now) {
+			m.remove(el)
+			m.stats.expirations.Add(1)
+		}
+	}
+}
+
+/*Stats get statistics */
+func (m *MapCache) Stats() MapCacheStats {
+	m.mu.Lock()
+	size := m.lru.Len()
+	m.mu.Unlock()
+	return MapCacheStats{
+		Hits:        m.stats.hits.Load(),
+		Misses:      m.stats.misses.Load(),
+		Evictions:   m.stats.evictions.Load(),
+		Expirations: m.stats.expirations.Load(),
+		Size:        size,
+	}
+}
+
+/*Stop stop the janitor, the cache is still usable */
+func (m *MapCache) Stop() {
+	m.stopOnce.Do(func() {
+		close(m.stop)
+	})
+}
+
+func (m *MapCache) janitor() {
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			m.DeleteExpired()
+		case <-m.stop:
+			return
+		}
+	}
+}
+
+// remove 须持有锁
+func (m *MapCache) remove(el *list.Element) {
+	m.lru.Remove(el)
+	delete(m.items, el.Value.(*cachedData).key)
 }
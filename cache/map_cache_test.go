@@ -18,3 +18,43 @@ func TestMapCache_SetWithTTL(t *testing.T) {
 	log.Println(cache.Get("hello"))
 	log.Println(cache.Get("key"))
 }
+
+// TestMapCache_Delete ...
+func TestMapCache_Delete(t *testing.T) {
+	m := cache.NewMapCache()
+	m.Set("webox.a.1", 1, 0)
+	m.Set("webox.a.2", 2, 0)
+	m.Set("webox.b.1", 3, 0)
+	m.Delete("webox.a.1")
+	if m.Has("webox.a.1") || !m.Has("webox.a.2") {
+		t.Fatal("Delete should only remove the exact key")
+	}
+	m.DeleteByPrefix("webox.a.")
+	if m.Has("webox.a.2") || !m.Has("webox.b.1") {
+		t.Fatal("DeleteByPrefix should only remove keys with the prefix")
+	}
+	m.Clear()
+	if m.Has("webox.b.1") || m.Stats().Size != 0 {
+		t.Fatal("Clear should remove all keys")
+	}
+}
+
+// TestMapCache_LRU 超出最大条目数时淘汰最久未使用的条目，janitor清理过期条目
+func TestMapCache_LRU(t *testing.T) {
+	m := cache.NewMapCache(cache.MapCacheMaxEntries(2), cache.MapCacheJanitor(5*time.Millisecond))
+	defer m.Stop()
+	m.Set("a", 1, 0)
+	m.Set("b", 2, 0)
+	m.Get("a")
+	m.Set("c", 3, 0)
+	if m.Get("b") != nil || m.Get("a") != 1 || m.Get("c") != 3 {
+		t.Fatal("expected b to be evicted")
+	}
+	m.Set("d", 4, time.Millisecond)
+	time.Sleep(30 * time.Millisecond)
+
+	stats := m.Stats()
+	if stats.Evictions != 2 || stats.Expirations != 1 || stats.Size != 1 || stats.Hits != 3 || stats.Misses != 1 {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+}
@@ -0,0 +1,303 @@
+package cache
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// RedisStore 通过RESP协议访问Redis的Store，内置连接池，不依赖第三方客户端
+// 共享同一Redis的多个进程可通过RegisterStore或各组件的缓存选项使用同一份token及ticket
+type RedisStore struct {
+	addr        string
+	password    string
+	db          int
+	prefix      string
+	poolSize    int
+	dialTimeout time.Duration
+	timeout     time.Duration
+	idle        chan *redisConn
+	active      chan struct{}
+	closed      atomic.Bool
+}
+
+type redisConn struct {
+	conn net.Conn
+	r    *bufio.Reader
+	w    *bufio.Writer
+}
+
+// RedisOption ...
+type RedisOption func(s *RedisStore)
+
+// RedisPassword 连接后使用AUTH认证
+func RedisPassword(password string) RedisOption {
+	return func(s *RedisStore) {
+		s.password = password
+	}
+}
+
+// RedisDB 连接后使用SELECT选择数据库
+func RedisDB(db int) RedisOption {
+	return func(s *RedisStore) {
+		s.db = db
+	}
+}
+
+// RedisPrefix 为所有key加上前缀，多个应用共用一个Redis时避免冲突
+func RedisPrefix(prefix string) RedisOption {
+	return func(s *RedisStore) {
+		s.prefix = prefix
+	}
+}
+
+// RedisPoolSize 最大连接数，同时也是最大空闲连接数
+func RedisPoolSize(n int) RedisOption {
+	return func(s *RedisStore) {
+		s.poolSize = n
+	}
+}
+
+// RedisTimeout 建立连接的超时时间及ctx未设置截止时间时每条命令的超时时间
+func RedisTimeout(dial, command time.Duration) RedisOption {
+	return func(s *RedisStore) {
+		s.dialTimeout = dial
+		s.timeout = command
+	}
+}
+
+// NewRedisStore addr为host:port
+func NewRedisStore(addr string, options ...RedisOption) *RedisStore {
+	s := &RedisStore{
+		addr:        addr,
+		poolSize:    10,
+		dialTimeout: 5 * time.Second,
+		timeout:     3 * time.Second,
+	}
+	for _, o := range options {
+		o(s)
+	}
+	if s.poolSize < 1 {
+		s.poolSize = 1
+	}
+	s.idle = make(chan *redisConn, s.poolSize)
+	s.active = make(chan struct{}, s.poolSize)
+	return s
+}
+
+// Get ...
+func (s *RedisStore) Get(ctx context.Context, key string) ([]byte, error) {
+	reply, e := s.Do(ctx, "GET", s.prefix+key)
+	if e != nil {
+		return nil, e
+	}
+	if reply == nil {
+		return nil, ErrNotFound
+	}
+	v, b := reply.([]byte)
+	if !b {
+		return nil, fmt.Errorf("redis: unexpected GET reply %T", reply)
+	}
+	return v, nil
+}
+
+// Set ttl不足1毫秒时按1毫秒设置
+func (s *RedisStore) Set(ctx context.Context, key string, val []byte, ttl time.Duration) error {
+	args := []any{"SET", s.prefix + key, val}
+	if ttl > 0 {
+		args = append(args, "PX", max(ttl.Milliseconds(), 1))
+	}
+	_, e := s.Do(ctx, args...)
+	return e
+}
+
+// Delete ...
+func (s *RedisStore) Delete(ctx context.Context, key string) error {
+	_, e := s.Do(ctx, "DEL", s.prefix+key)
+	return e
+}
+
+// SetNX 使用SET NX PX保证设置值与过期时间的原子性
+func (s *RedisStore) SetNX(ctx context.Context, key string, val []byte, ttl time.Duration) (bool, error) {
+	args := []any{"SET", s.prefix + key, val, "NX"}
+	if ttl > 0 {
+		args = append(args, "PX", max(ttl.Milliseconds(), 1))
+	}
+	reply, e := s.Do(ctx, args...)
+	if e != nil {
+		return false, e
+	}
+	return reply != nil, nil
+}
+
+// redisCompareAndDelete 值等于ARGV[1]时删除KEYS[1]
+const redisCompareAndDelete = `if redis.call("GET", KEYS[1]) == ARGV[1] then return redis.call("DEL", KEYS[1]) else return 0 end`
+
+// CompareAndDelete 使用EVAL执行脚本保证比较与删除的原子性
+func (s *RedisStore) CompareAndDelete(ctx context.Context, key string, val []byte) (bool, error) {
+	reply, e := s.Do(ctx, "EVAL", redisCompareAndDelete, 1, s.prefix+key, val)
+	if e != nil {
+		return false, e
+	}
+	n, b := reply.(int64)
+	if !b {
+		return false, fmt.Errorf("redis: unexpected EVAL reply %v", reply)
+	}
+	return n == 1, nil
+}
+
+// DeleteByPrefix 使用SCAN遍历前缀匹配的key并分批删除
+func (s *RedisStore) DeleteByPrefix(ctx context.Context, prefix string) error {
+	cursor := "0"
+	for {
+		reply, e := s.Do(ctx, "SCAN", cursor, "MATCH", escapeGlob(s.prefix+prefix)+"*", "COUNT", 100)
+		if e != nil {
+			return e
+		}
+		items, b := reply.([]any)
+		if !b || len(items) != 2 {
+			return fmt.Errorf("redis: unexpected SCAN reply %v", reply)
+		}
+		next, _ := items[0].([]byte)
+		keys, _ := items[1].([]any)
+		if len(keys) > 0 {
+			if _, e = s.Do(ctx, append([]any{"DEL"}, keys...)...); e != nil {
+				return e
+			}
+		}
+		cursor = string(next)
+		if cursor == "0" || cursor == "" {
+			return nil
+		}
+	}
+}
+
+// Do 执行一条命令，Redis的错误回复以RedisError返回
+func (s *RedisStore) Do(ctx context.Context, args ...any) (any, error) {
+	c, e := s.get(ctx)
+	if e != nil {
+		return nil, e
+	}
+	reply, e := s.exec(ctx, c, args...)
+	s.put(c, e)
+	if e != nil {
+		return nil, e
+	}
+	if re, b := reply.(RedisError); b {
+		return nil, re
+	}
+	return reply, nil
+}
+
+// Close 关闭所有空闲连接，正在使用的连接归还时关闭
+func (s *RedisStore) Close() error {
+	s.closed.Store(true)
+	for {
+		select {
+		case c := <-s.idle:
+			_ = c.conn.Close()
+		default:
+			return nil
+		}
+	}
+}
+
+func (s *RedisStore) exec(ctx context.Context, c *redisConn, args ...any) (any, error) {
+	deadline, b := ctx.Deadline()
+	if !b {
+		deadline = time.Now().Add(s.timeout)
+	}
+	if e := c.conn.SetDeadline(deadline); e != nil {
+		return nil, e
+	}
+	if e := writeCommand(c.w, args...); e != nil {
+		return nil, e
+	}
+	return readReply(c.r)
+}
+
+// get 优先使用空闲连接，连接数已满时等待归还或ctx结束
+func (s *RedisStore) get(ctx context.Context) (*redisConn, error) {
+	if s.closed.Load() {
+		return nil, ErrRedisClosed
+	}
+	select {
+	case s.active <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	select {
+	case c := <-s.idle:
+		return c, nil
+	default:
+	}
+	c, e := s.dial(ctx)
+	if e != nil {
+		<-s.active
+		return nil, e
+	}
+	return c, nil
+}
+
+// put 命令出现网络或协议错误的连接直接关闭
+func (s *RedisStore) put(c *redisConn, e error) {
+	defer func() { <-s.active }()
+	if e != nil || s.closed.Load() {
+		_ = c.conn.Close()
+		return
+	}
+	select {
+	case s.idle <- c:
+	default:
+		_ = c.conn.Close()
+	}
+}
+
+func (s *RedisStore) dial(ctx context.Context) (*redisConn, error) {
+	dialer := net.Dialer{Timeout: s.dialTimeout}
+	conn, e := dialer.DialContext(ctx, "tcp", s.addr)
+	if e != nil {
+		return nil, e
+	}
+	c := &redisConn{conn: conn, r: bufio.NewReader(conn), w: bufio.NewWriter(conn)}
+	var setup [][]any
+	if s.password != "" {
+		setup = append(setup, []any{"AUTH", s.password})
+	}
+	if s.db != 0 {
+		setup = append(setup, []any{"SELECT", s.db})
+	}
+	for _, args := range setup {
+		reply, e := s.exec(ctx, c, args...)
+		if e == nil {
+			if re, b := reply.(RedisError); b {
+				e = re
+			}
+		}
+		if e != nil {
+			_ = conn.Close()
+			return nil, fmt.Errorf("redis %s: %w", args[0], e)
+		}
+	}
+	return c, nil
+}
+
+// escapeGlob 转义SCAN MATCH中的通配符
+func escapeGlob(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if strings.ContainsRune(`*?[]\`, r) {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// ErrRedisClosed RedisStore已关闭
+var ErrRedisClosed = errors.New("redis: store closed")
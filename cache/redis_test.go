@@ -0,0 +1,245 @@
+package cache_test
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"webox/cache"
+)
+
+// respServer 测试用的RESP服务，支持AUTH/GET/SET(PX,NX)/DEL/SCAN及比较删除的EVAL脚本
+type respServer struct {
+	ln    net.Listener
+	mu    sync.Mutex
+	data  map[string]string
+	ttl   map[string]time.Time
+	conns int
+}
+
+func newRESPServer(t *testing.T) *respServer {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &respServer{ln: ln, data: map[string]string{}, ttl: map[string]time.Time{}}
+	t.Cleanup(func() { _ = ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			s.mu.Lock()
+			s.conns++
+			s.mu.Unlock()
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+func (s *respServer) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	authed := false
+	for {
+		args, err := readCommand(r)
+		if err != nil {
+			return
+		}
+		cmd := strings.ToUpper(args[0])
+		if cmd == "AUTH" {
+			authed = args[1] == "secret"
+			if !authed {
+				_, _ = io.WriteString(conn, "-WRONGPASS invalid password\r\n")
+				continue
+			}
+			_, _ = io.WriteString(conn, "+OK\r\n")
+			continue
+		}
+		if !authed {
+			_, _ = io.WriteString(conn, "-NOAUTH Authentication required.\r\n")
+			continue
+		}
+		_, _ = io.WriteString(conn, s.exec(cmd, args[1:]))
+	}
+}
+
+func (s *respServer) exec(cmd string, args []string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for k, v := range s.ttl {
+		if v.Before(time.Now()) {
+			delete(s.data, k)
+			delete(s.ttl, k)
+		}
+	}
+	switch cmd {
+	case "GET":
+		if v, b := s.data[args[0]]; b {
+			return fmt.Sprintf("$%d\r\n%s\r\n", len(v), v)
+		}
+		return "$-1\r\n"
+	case "SET":
+		nx, px := false, 0
+		for i := 2; i < len(args); i++ {
+			switch strings.ToUpper(args[i]) {
+			case "NX":
+				nx = true
+			case "PX":
+				i++
+				px, _ = strconv.Atoi(args[i])
+			}
+		}
+		if _, b := s.data[args[0]]; b && nx {
+			return "$-1\r\n"
+		}
+		s.data[args[0]] = args[1]
+		delete(s.ttl, args[0])
+		if px > 0 {
+			s.ttl[args[0]] = time.Now().Add(time.Duration(px) * time.Millisecond)
+		}
+		return "+OK\r\n"
+	case "DEL":
+		n := 0
+		for _, k := range args {
+			if _, b := s.data[k]; b {
+				delete(s.data, k)
+				n++
+			}
+		}
+		return fmt.Sprintf(":%d\r\n", n)
+	case "EVAL":
+		// 仅模拟RedisStore.CompareAndDelete的脚本
+		if v, b := s.data[args[2]]; b && v == args[3] {
+			delete(s.data, args[2])
+			delete(s.ttl, args[2])
+			return ":1\r\n"
+		}
+		return ":0\r\n"
+	case "SCAN":
+		// 每次返回一个key以覆盖游标迭代，游标为上次返回的key
+		var next string
+		for k := range s.data {
+			if ok, _ := path.Match(args[2], k); ok && (args[0] == "0" || k > args[0]) && (next == "" || k < next) {
+				next = k
+			}
+		}
+		if next == "" {
+			return "*2\r\n$1\r\n0\r\n*0\r\n"
+		}
+		return fmt.Sprintf("*2\r\n$%d\r\n%s\r\n*1\r\n$%d\r\n%s\r\n", len(next), next, len(next), next)
+	}
+	return "-ERR unknown command '" + cmd + "'\r\n"
+}
+
+func (s *respServer) get(key string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.data[key]
+}
+
+func (s *respServer) connCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.conns
+}
+
+func readCommand(r *bufio.Reader) ([]string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(line[1:]))
+	if err != nil {
+		return nil, err
+	}
+	args := make([]string, n)
+	for i := range args {
+		if line, err = r.ReadString('\n'); err != nil {
+			return nil, err
+		}
+		size, _ := strconv.Atoi(strings.TrimSpace(line[1:]))
+		b := make([]byte, size+2)
+		if _, err = io.ReadFull(r, b); err != nil {
+			return nil, err
+		}
+		args[i] = string(b[:size])
+	}
+	return args, nil
+}
+
+// TestRedisStore ...
+func TestRedisStore(t *testing.T) {
+	srv := newRESPServer(t)
+	ctx := context.Background()
+
+	if _, err := cache.NewRedisStore(srv.ln.Addr().String()).Get(ctx, "k"); err == nil || !strings.Contains(err.Error(), "NOAUTH") {
+		t.Fatalf("expected NOAUTH error, got %v", err)
+	}
+
+	store := cache.NewRedisStore(srv.ln.Addr().String(), cache.RedisPassword("secret"), cache.RedisPrefix("app:"), cache.RedisPoolSize(2))
+	defer store.Close()
+	if _, err := store.Get(ctx, "k"); !errors.Is(err, cache.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+	if err := store.Set(ctx, "webox.ticket", []byte("ticket"), time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := store.Get(ctx, "webox.ticket"); string(v) != "ticket" || srv.get("app:webox.ticket") != "ticket" {
+		t.Fatalf("unexpected value %q", v)
+	}
+	if ok, _ := store.SetNX(ctx, "webox.ticket", []byte("other"), time.Hour); ok {
+		t.Fatal("SetNX should fail on existing key")
+	}
+	if ok, err := store.SetNX(ctx, "lock", []byte("owner"), 5*time.Millisecond); !ok || err != nil {
+		t.Fatalf("SetNX should succeed: %v", err)
+	}
+	time.Sleep(10 * time.Millisecond)
+	if _, err := store.Get(ctx, "lock"); !errors.Is(err, cache.ErrNotFound) {
+		t.Fatal("value should expire after PX")
+	}
+	if ok, _ := store.CompareAndDelete(ctx, "webox.ticket", []byte("other")); ok || srv.get("app:webox.ticket") != "ticket" {
+		t.Fatal("CompareAndDelete should keep a different value")
+	}
+	_ = store.Set(ctx, "owned", []byte("owner"), time.Hour)
+	if ok, err := store.CompareAndDelete(ctx, "owned", []byte("owner")); !ok || err != nil || srv.get("app:owned") != "" {
+		t.Fatalf("CompareAndDelete should delete a matching value: %v", err)
+	}
+
+	c := cache.NewCacheAdapter(store, time.Second)
+	for _, k := range []string{"webox.jssdk.a", "webox.jssdk.b", "webox.jssdk.c"} {
+		c.Set(k, "v", 0)
+	}
+	c.Set("webox.jssdk*x", "v", 0)
+	c.DeleteByPrefix("webox.jssdk.")
+	if c.Has("webox.jssdk.a") || c.Has("webox.jssdk.c") || !c.Has("webox.jssdk*x") || !c.Has("webox.ticket") {
+		t.Fatal("unexpected data after DeleteByPrefix")
+	}
+
+	var wg sync.WaitGroup
+	for range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := store.Get(ctx, "webox.ticket"); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	// 1个未认证的连接加上连接池中至多2个连接
+	if n := srv.connCount(); n > 3 {
+		t.Fatalf("got %d connections, want at most 3", n)
+	}
+}
@@ -0,0 +1,109 @@
+package cache
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// RedisError Redis返回的错误回复，如"ERR unknown command"
+type RedisError string
+
+// Error ...
+func (e RedisError) Error() string {
+	return string(e)
+}
+
+// writeCommand 以RESP数组的形式写入命令，参数均作为bulk string
+func writeCommand(w *bufio.Writer, args ...any) error {
+	if _, e := fmt.Fprintf(w, "*%d\r\n", len(args)); e != nil {
+		return e
+	}
+	for _, arg := range args {
+		var b []byte
+		switch v := arg.(type) {
+		case []byte:
+			b = v
+		case string:
+			b = []byte(v)
+		case int:
+			b = strconv.AppendInt(nil, int64(v), 10)
+		case int64:
+			b = strconv.AppendInt(nil, v, 10)
+		default:
+			return fmt.Errorf("redis: unsupported argument type %T", arg)
+		}
+		if _, e := fmt.Fprintf(w, "$%d\r\n", len(b)); e != nil {
+			return e
+		}
+		if _, e := w.Write(b); e != nil {
+			return e
+		}
+		if _, e := w.WriteString("\r\n"); e != nil {
+			return e
+		}
+	}
+	return w.Flush()
+}
+
+// readReply 读取一个RESP回复
+// 简单字符串返回string，整数返回int64，bulk string返回[]byte，数组返回[]any，nil回复返回nil，错误回复返回RedisError
+func readReply(r *bufio.Reader) (any, error) {
+	line, e := readLine(r)
+	if e != nil {
+		return nil, e
+	}
+	if len(line) == 0 {
+		return nil, errors.New("redis: empty reply")
+	}
+	switch line[0] {
+	case '+':
+		return string(line[1:]), nil
+	case '-':
+		return RedisError(line[1:]), nil
+	case ':':
+		return strconv.ParseInt(string(line[1:]), 10, 64)
+	case '$':
+		n, e := strconv.Atoi(string(line[1:]))
+		if e != nil {
+			return nil, e
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		b := make([]byte, n+2)
+		if _, e = io.ReadFull(r, b); e != nil {
+			return nil, e
+		}
+		return b[:n], nil
+	case '*':
+		n, e := strconv.Atoi(string(line[1:]))
+		if e != nil {
+			return nil, e
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		items := make([]any, n)
+		for i := range items {
+			if items[i], e = readReply(r); e != nil {
+				return nil, e
+			}
+		}
+		return items, nil
+	}
+	return nil, fmt.Errorf("redis: unexpected reply %q", line)
+}
+
+func readLine(r *bufio.Reader) ([]byte, error) {
+	line, e := r.ReadSlice('\n')
+	if e != nil {
+		return nil, e
+	}
+	if len(line) < 2 || line[len(line)-2] != '\r' {
+		return nil, fmt.Errorf("redis: malformed line %q", line)
+	}
+	return line[:len(line)-2], nil
+}
@@ -0,0 +1,287 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+	"webox/util"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// ErrNotFound key不存在或已过期
+var ErrNotFound = errors.New("cache: key not found")
+
+/*Store define a context-aware cache interface (v2), values are stored as bytes so that remote caches can hold them */
+type Store interface {
+	// Get 获取值，key不存在或已过期时返回ErrNotFound
+	Get(ctx context.Context, key string) ([]byte, error)
+	// Set 设置值，ttl为0时不过期
+	Set(ctx context.Context, key string, val []byte, ttl time.Duration) error
+	// Delete 删除值，key不存在时不返回错误
+	Delete(ctx context.Context, key string) error
+	// SetNX key不存在时设置值并返回true，已存在时返回false
+	SetNX(ctx context.Context, key string, val []byte, ttl time.Duration) (bool, error)
+	// CompareAndDelete 值等于val时删除并返回true，比较与删除须为原子操作
+	CompareAndDelete(ctx context.Context, key string, val []byte) (bool, error)
+}
+
+// GetString 以string获取值
+func GetString(ctx context.Context, s Store, key string) (string, error) {
+	v, e := s.Get(ctx, key)
+	if e != nil {
+		return "", e
+	}
+	return string(v), nil
+}
+
+// SetString 以string设置值
+func SetString(ctx context.Context, s Store, key, val string, ttl time.Duration) error {
+	return s.Set(ctx, key, []byte(val), ttl)
+}
+
+// Loader 缓存不存在时加载值，返回值及其缓存时间
+type Loader func(ctx context.Context) (val []byte, ttl time.Duration, e error)
+
+// loadFlight 合并进程内对同一Store同一key的并发加载
+var loadFlight util.SingleFlight
+
+// GetOrLoad 获取值，不存在时调用loader加载并写入缓存，进程内对同一Store同一key的并发加载只执行一次
+// loader panic时等待中的调用得到错误，panic在加载的调用中重新抛出
+func GetOrLoad(ctx context.Context, s Store, key string, loader Loader) ([]byte, error) {
+	v, e := s.Get(ctx, key)
+	if e == nil || !errors.Is(e, ErrNotFound) {
+		return v, e
+	}
+
+	//不同Store的同名key分别加载，确保每个Store都会写入
+	flightKey := fmt.Sprintf("%T.%p.%s", s, s, key)
+	val, e, _ := loadFlight.DoContext(ctx, flightKey, func() (any, error) {
+		val, ttl, e := loader(ctx)
+		if e != nil {
+			return nil, e
+		}
+		return val, s.Set(ctx, key, val, ttl)
+	})
+	if e != nil {
+		return nil, e
+	}
+	return val.([]byte), nil
+}
+
+/*StoreAdapter 将Cache适配为Store，SetNX及CompareAndDelete仅在进程内保证原子性 */
+type StoreAdapter struct {
+	mu    sync.Mutex
+	cache Cache
+}
+
+// NewStoreAdapter ...
+func NewStoreAdapter(c Cache) *StoreAdapter {
+	return &StoreAdapter{cache: c}
+}
+
+// Get ...
+func (a *StoreAdapter) Get(ctx context.Context, key string) ([]byte, error) {
+	if e := ctx.Err(); e != nil {
+		return nil, e
+	}
+	v := a.cache.Get(key)
+	if v == nil {
+		return nil, ErrNotFound
+	}
+	return encodeValue(v)
+}
+
+// Set ...
+func (a *StoreAdapter) Set(ctx context.Context, key string, val []byte, ttl time.Duration) error {
+	if e := ctx.Err(); e != nil {
+		return e
+	}
+	a.cache.Set(key, val, ttl)
+	return nil
+}
+
+// Delete ...
+func (a *StoreAdapter) Delete(ctx context.Context, key string) error {
+	if e := ctx.Err(); e != nil {
+		return e
+	}
+	a.cache.Delete(key)
+	return nil
+}
+
+// SetNX ...
+func (a *StoreAdapter) SetNX(ctx context.Context, key string, val []byte, ttl time.Duration) (bool, error) {
+	if e := ctx.Err(); e != nil {
+		return false, e
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.cache.Has(key) {
+		return false, nil
+	}
+	a.cache.Set(key, val, ttl)
+	return true, nil
+}
+
+// CompareAndDelete ...
+func (a *StoreAdapter) CompareAndDelete(ctx context.Context, key string, val []byte) (bool, error) {
+	if e := ctx.Err(); e != nil {
+		return false, e
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	v := a.cache.Get(key)
+	if v == nil {
+		return false, nil
+	}
+	b, e := encodeValue(v)
+	if e != nil || !bytes.Equal(b, val) {
+		return false, e
+	}
+	a.cache.Delete(key)
+	return true, nil
+}
+
+// CacheAdapter 将Store适配为Cache，以便RegisterCache及包级函数使用
+// string及[]byte原样保存，其他值保存为JSON；Get返回string，保存为JSON的值须由调用方自行解析
+type CacheAdapter struct {
+	store   Store
+	timeout time.Duration
+}
+
+// NewCacheAdapter timeout为每次操作的超时时间，为0时不限制
+func NewCacheAdapter(s Store, timeout time.Duration) *CacheAdapter {
+	return &CacheAdapter{store: s, timeout: timeout}
+}
+
+// Store ...
+func (a *CacheAdapter) Store() Store {
+	return a.store
+}
+
+func (a *CacheAdapter) context() (context.Context, context.CancelFunc) {
+	if a.timeout > 0 {
+		return context.WithTimeout(context.Background(), a.timeout)
+	}
+	return context.WithCancel(context.Background())
+}
+
+// Add ...
+func (a *CacheAdapter) Add(key string, val any) {
+	a.Set(key, val, 0)
+}
+
+// Set ...
+func (a *CacheAdapter) Set(key string, val any, ttl time.Duration) {
+	v, e := encodeValue(val)
+	if e != nil {
+		return
+	}
+	ctx, cancel := a.context()
+	defer cancel()
+	_ = a.store.Set(ctx, key, v, ttl)
+}
+
+// Get ...
+func (a *CacheAdapter) Get(key string) any {
+	ctx, cancel := a.context()
+	defer cancel()
+	v, e := a.store.Get(ctx, key)
+	if e != nil {
+		return nil
+	}
+	return string(v)
+}
+
+// Has ...
+func (a *CacheAdapter) Has(key string) bool {
+	return a.Get(key) != nil
+}
+
+// Delete ...
+func (a *CacheAdapter) Delete(key string) {
+	ctx, cancel := a.context()
+	defer cancel()
+	_ = a.store.Delete(ctx, key)
+}
+
+// DeleteByPrefix Store实现了DeleteByPrefix(如RedisStore)时按前缀删除，否则不做任何处理
+func (a *CacheAdapter) DeleteByPrefix(prefix string) {
+	d, b := a.store.(interface {
+		DeleteByPrefix(ctx context.Context, prefix string) error
+	})
+	if !b {
+		return
+	}
+	ctx, cancel := a.context()
+	defer cancel()
+	_ = d.DeleteByPrefix(ctx, prefix)
+}
+
+// Clear Store实现了DeleteByPrefix时删除全部key(RedisStore只删除其前缀下的key)，否则不做任何处理
+func (a *CacheAdapter) Clear() {
+	a.DeleteByPrefix("")
+}
+
+// encodeValue string及[]byte原样返回，其他值编码为JSON
+func encodeValue(v any) ([]byte, error) {
+	switch vv := v.(type) {
+	case []byte:
+		return vv, nil
+	case string:
+		return []byte(vv), nil
+	}
+	return jsoniter.Marshal(v)
+}
+
+/*StoreLocker 基于Store.SetNX及Store.CompareAndDelete的Locker，Store为共享的远程缓存时可跨进程加锁 */
+type StoreLocker struct {
+	store Store
+	retry time.Duration
+}
+
+// NewStoreLocker retry为获取锁失败后的重试间隔
+func NewStoreLocker(s Store, retry time.Duration) *StoreLocker {
+	if retry <= 0 {
+		retry = 50 * time.Millisecond
+	}
+	return &StoreLocker{store: s, retry: retry}
+}
+
+// Lock 锁的值为随机串，释放时以CompareAndDelete原子地删除仍属于自己的锁，
+// 锁已过期并被其他进程获取时不会误删
+func (l *StoreLocker) Lock(ctx context.Context, key string, ttl time.Duration) (unlock func(), e error) {
+	b := make([]byte, 16)
+	if _, e = rand.Read(b); e != nil {
+		return nil, e
+	}
+	owner := hex.EncodeToString(b)
+	for {
+		ok, e := l.store.SetNX(ctx, key, []byte(owner), ttl)
+		if e != nil {
+			return nil, e
+		}
+		if ok {
+			break
+		}
+		select {
+		case <-time.After(l.retry):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			_, _ = l.store.CompareAndDelete(ctx, key, []byte(owner))
+		})
+	}, nil
+}
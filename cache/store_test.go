@@ -0,0 +1,235 @@
+package cache_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"webox/cache"
+)
+
+// TestStoreAdapter ...
+func TestStoreAdapter(t *testing.T) {
+	ctx := context.Background()
+	store := cache.NewStoreAdapter(cache.NewMapCache())
+	if _, err := store.Get(ctx, "k"); !errors.Is(err, cache.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+	if ok, err := store.SetNX(ctx, "k", []byte("v1"), time.Minute); !ok || err != nil {
+		t.Fatalf("first SetNX should succeed: %v %v", ok, err)
+	}
+	if ok, _ := store.SetNX(ctx, "k", []byte("v2"), time.Minute); ok {
+		t.Fatal("second SetNX should fail")
+	}
+	if v, _ := cache.GetString(ctx, store, "k"); v != "v1" {
+		t.Fatalf("got %q, want v1", v)
+	}
+
+	var loads int32
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			v, err := cache.GetOrLoad(ctx, store, "lazy", func(ctx context.Context) ([]byte, time.Duration, error) {
+				atomic.AddInt32(&loads, 1)
+				time.Sleep(20 * time.Millisecond)
+				return []byte("loaded"), time.Minute, nil
+			})
+			if err != nil || string(v) != "loaded" {
+				t.Errorf("unexpected load result %q %v", v, err)
+			}
+		}()
+	}
+	wg.Wait()
+	if loads != 1 {
+		t.Fatalf("got %d loads, want 1", loads)
+	}
+}
+
+// TestCacheAdapter 通过Store实现的Cache供包级函数使用
+func TestCacheAdapter(t *testing.T) {
+	c := cache.NewCacheAdapter(cache.NewStoreAdapter(cache.NewMapCache()), time.Second)
+	c.Set("s", "ticket", time.Minute)
+	c.Set("m", map[string]int{"a": 1}, time.Minute)
+	if c.Get("s") != "ticket" || c.Get("m") != `{"a":1}` || c.Has("none") {
+		t.Fatalf("unexpected values %v %v", c.Get("s"), c.Get("m"))
+	}
+
+	locker := cache.NewStoreLocker(c.Store(), time.Millisecond)
+	unlock, err := locker.Lock(context.Background(), "lock", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err = locker.Lock(ctx, "lock", time.Minute); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+	unlock()
+	if _, err = locker.Lock(context.Background(), "lock", time.Minute); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestStoreLocker_Expired 锁过期后被其他持有者获取时，原持有者释放锁不会删除新锁
+func TestStoreLocker_Expired(t *testing.T) {
+	s := cache.NewStoreAdapter(cache.NewMapCache())
+	locker := cache.NewStoreLocker(s, time.Millisecond)
+	ctx := context.Background()
+
+	unlock, err := locker.Lock(ctx, "lock", 5*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(10 * time.Millisecond)
+	unlock2, err := locker.Lock(ctx, "lock", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	unlock()
+	if ok, _ := s.SetNX(ctx, "lock", []byte("other"), time.Minute); ok {
+		t.Fatal("expired owner released the new lock")
+	}
+	unlock2()
+	if ok, _ := s.SetNX(ctx, "lock", []byte("other"), time.Minute); !ok {
+		t.Fatal("lock should be released by its owner")
+	}
+}
+
+// TestGetOrLoad_Panic loader panic时加载者重新panic，等待者得到错误
+func TestGetOrLoad_Panic(t *testing.T) {
+	s := cache.NewStoreAdapter(cache.NewMapCache())
+	ctx := context.Background()
+	started := make(chan struct{})
+
+	var wg sync.WaitGroup
+	var waiterErr error
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		<-started
+		_, waiterErr = cache.GetOrLoad(ctx, s, "key", func(context.Context) ([]byte, time.Duration, error) {
+			return []byte("unexpected"), 0, nil
+		})
+	}()
+
+	func() {
+		defer func() {
+			if r := recover(); r != "boom" {
+				t.Errorf("got panic %v, want boom", r)
+			}
+		}()
+		_, _ = cache.GetOrLoad(ctx, s, "key", func(context.Context) ([]byte, time.Duration, error) {
+			close(started)
+			time.Sleep(50 * time.Millisecond)
+			panic("boom")
+		})
+	}()
+	wg.Wait()
+
+	if waiterErr == nil {
+		t.Fatal("waiter should get an error when the loader panics")
+	}
+	if v, e := cache.GetOrLoad(ctx, s, "key", func(context.Context) ([]byte, time.Duration, error) {
+		return []byte("ok"), 0, nil
+	}); e != nil || string(v) != "ok" {
+		t.Fatalf("got %s %v after panic", v, e)
+	}
+}
+
+// TestGetOrLoad_Stores 不同Store的同名key分别加载并写入各自的Store
+func TestGetOrLoad_Stores(t *testing.T) {
+	s1 := cache.NewStoreAdapter(cache.NewMapCache())
+	s2 := cache.NewStoreAdapter(cache.NewMapCache())
+	ctx := context.Background()
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		_, _ = cache.GetOrLoad(ctx, s1, "key", func(context.Context) ([]byte, time.Duration, error) {
+			close(started)
+			<-release
+			return []byte("v1"), time.Minute, nil
+		})
+	}()
+	<-started
+	v, err := cache.GetOrLoad(ctx, s2, "key", func(context.Context) ([]byte, time.Duration, error) {
+		return []byte("v2"), time.Minute, nil
+	})
+	close(release)
+	wg.Wait()
+	if err != nil || string(v) != "v2" {
+		t.Fatalf("got %s %v, want v2", v, err)
+	}
+	for s, want := range map[cache.Store]string{s1: "v1", s2: "v2"} {
+		if v, err := s.Get(ctx, "key"); err != nil || string(v) != want {
+			t.Fatalf("got %s %v, want %s", v, err, want)
+		}
+	}
+}
+
+// TestGetOrLoad_WaiterCancel 等待其他调用加载时ctx结束则返回ctx的错误
+func TestGetOrLoad_WaiterCancel(t *testing.T) {
+	s := cache.NewStoreAdapter(cache.NewMapCache())
+	started := make(chan struct{})
+	release := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, _ = cache.GetOrLoad(context.Background(), s, "key", func(context.Context) ([]byte, time.Duration, error) {
+			close(started)
+			<-release
+			return []byte("v"), time.Minute, nil
+		})
+	}()
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := cache.GetOrLoad(ctx, s, "key", func(context.Context) ([]byte, time.Duration, error) {
+		return []byte("unexpected"), 0, nil
+	}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+	close(release)
+	<-done
+}
+
+// TestCacheAdapter_Clear Store支持按前缀删除时清空全部key
+func TestCacheAdapter_Clear(t *testing.T) {
+	c := cache.NewCacheAdapter(&prefixStore{StoreAdapter: cache.NewStoreAdapter(cache.NewMapCache())}, time.Second)
+	c.Set("a", "1", time.Minute)
+	c.Set("b", "2", time.Minute)
+	c.Clear()
+	if c.Has("a") || c.Has("b") {
+		t.Fatal("expected all keys to be cleared")
+	}
+}
+
+// prefixStore 以StoreAdapter实现DeleteByPrefix
+type prefixStore struct {
+	*cache.StoreAdapter
+	keys []string
+}
+
+func (s *prefixStore) Set(ctx context.Context, key string, val []byte, ttl time.Duration) error {
+	s.keys = append(s.keys, key)
+	return s.StoreAdapter.Set(ctx, key, val, ttl)
+}
+
+func (s *prefixStore) DeleteByPrefix(ctx context.Context, prefix string) error {
+	for _, key := range s.keys {
+		if strings.HasPrefix(key, prefix) {
+			_ = s.Delete(ctx, key)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package webox
+
+import (
+	"strings"
+	"webox/cache"
+)
+
+// DefaultCacheNamespace 缓存key的默认命名空间
+const DefaultCacheNamespace = "webox"
+
+// cacheScope 组件使用的缓存实例及key命名空间，未设置时使用全局缓存及DefaultCacheNamespace
+type cacheScope struct {
+	cache     cache.Cache
+	namespace string
+}
+
+func (s *cacheScope) setCache(c cache.Cache, namespace string) {
+	s.cache = c
+	s.namespace = namespace
+}
+
+// getCache 每次调用时取全局缓存，RegisterCache在组件创建后调用也能生效
+func (s *cacheScope) getCache() cache.Cache {
+	if s.cache != nil {
+		return s.cache
+	}
+	return cache.Default()
+}
+
+func (s *cacheScope) cacheKey(parts ...string) string {
+	ns := DefaultCacheNamespace
+	if s.namespace != "" {
+		ns = s.namespace
+	}
+	return ns + "." + strings.Join(parts, ".")
+}
+
+// inherit 未设置缓存时沿用parent的缓存实例及命名空间
+func (s *cacheScope) inherit(parent *cacheScope) {
+	if s.isDefault() {
+		*s = *parent
+	}
+}
+
+func (s *cacheScope) isDefault() bool {
+	return s.cache == nil && s.namespace == ""
+}
@@ -3,7 +3,6 @@ package webox
 import (
 	"context"
 	"crypto/tls"
-	"errors"
 	"fmt"
 	"io"
 	"log"
@@ -89,19 +88,19 @@ func (obj *Client) parse(options ...ClientOption) {
 	}
 }
 
-// GetToken ...
+// GetToken 获取请求附加的access_token参数，未设置AccessToken时返回空参数
 func (obj *Client) GetToken() (util.Map, error) {
-	if obj.AccessToken != nil {
-		return obj.AccessToken.KeyMap(), nil
+	if obj.AccessToken == nil {
+		return util.Map{}, nil
 	}
-	return nil, errors.New("nil AccessToken")
+	return KeyMap(obj.AccessToken)
 }
 
-// MustToken ...
+// MustToken 获取access_token参数，失败时返回空参数
 func (obj *Client) MustToken() (token util.Map) {
 	token, err := obj.GetToken()
 	if err != nil {
-		panic(err)
+		return util.Map{}
 	}
 	return token
 }
@@ -109,25 +108,76 @@ func (obj *Client) MustToken() (token util.Map) {
 // Post ...
 func (obj *Client) Post(ctx context.Context, url string, query util.Map, body any) Responder {
 
-	return obj.do(ctx, &RequestContent{
-		Method: api.POST,
-		URL:    url,
-		Query:  util.CombineMaps(query, obj.MustToken()),
-		Body:   buildBody(body, obj.BodyType),
-	})
+	return obj.doWithToken(ctx, api.POST, url, query, buildBody(body, obj.BodyType), true)
 }
 
 // Get ...
 func (obj *Client) Get(ctx context.Context, url string, query util.Map) Responder {
 
+	return obj.doWithToken(ctx, api.GET, url, query, buildBody(nil, obj.BodyType), true)
+}
+
+// Upload 以multipart/form-data上传文件，multi的取值规则见newMultipartBody
+// 以*MultipartFile上传时其Reader只能读取一次，token失效时不会重放请求
+func (obj *Client) Upload(ctx context.Context, url string, query, multi util.Map) Responder {
+
+	return obj.doWithToken(ctx, api.POST, url, query, buildBody(multi, BodyTypeMultipart), !hasMultipartFile(multi))
+}
+
+// doWithToken 附加access_token发送请求，返回token无效或过期的错误码时刷新token并重放一次
+func (obj *Client) doWithToken(ctx context.Context, method, url string, query util.Map, body *RequestBody, replay bool) Responder {
+	token, e := obj.GetToken()
+	if e != nil {
+		return ErrResponder(fmt.Errorf("get access token: %w", e))
+	}
+	resp := obj.do(ctx, &RequestContent{
+		Method: method,
+		URL:    url,
+		Query:  util.CombineMaps(query, token),
+		Body:   body,
+	})
+	if !replay || obj.AccessToken == nil || !IsTokenInvalid(resp) {
+		return resp
+	}
+
+	stale, _ := token[api.AccessTokenKey].(string)
+	renewed, e := obj.AccessToken.renewToken(ctx, true, stale)
+	if e != nil {
+		return resp
+	}
 	return obj.do(ctx, &RequestContent{
-		Method: api.POST,
+		Method: method,
 		URL:    url,
-		Query:  util.CombineMaps(query, obj.MustToken()),
-		Body:   buildBody(nil, obj.BodyType),
+		Query:  util.CombineMaps(query, renewed.KeyMap()),
+		Body:   body,
 	})
 }
 
+// Stream 发送请求并返回未读取的响应，用于下载素材等大文件，调用方负责关闭响应Body
+func (obj *Client) Stream(ctx context.Context, method, url string, query util.Map, body any) (*http.Response, error) {
+	client, e := obj.HTTPClient()
+	if e != nil {
+		return nil, fmt.Errorf("client build err:%+v", e)
+	}
+	token, e := obj.GetToken()
+	if e != nil {
+		return nil, fmt.Errorf("get access token: %w", e)
+	}
+	content := &RequestContent{
+		Method: method,
+		URL:    url,
+		Query:  util.CombineMaps(query, token),
+	}
+	if body != nil {
+		content.Body = buildBody(body, obj.BodyType)
+	}
+	request, e := content.BuildRequest()
+	if e != nil {
+		return nil, fmt.Errorf("request build err:%+v", e)
+	}
+	return client.Do(request.WithContext(ctx))
+}
+
 // HTTPClient ...
 func (obj *Client) HTTPClient() (*http.Client, error) {
 	return buildHTTPClient(obj, obj.UseSafe())
@@ -195,7 +245,12 @@ func Get(url string, query util.Map) Responder {
 
 // Context ...
 func Context() (context.Context, context.CancelFunc) {
-	return context.WithTimeout(context.Background(), 30*time.Second)
+	return contextWithTimeout(context.Background())
+}
+
+// contextWithTimeout 在parent的基础上设置与Context相同的超时时间
+func contextWithTimeout(parent context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(parent, 30*time.Second)
 }
 
 func buildTransport(client *Client) (*http.Transport, error) {
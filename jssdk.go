@@ -1,13 +1,15 @@
 package webox
 
 import (
+	"context"
 	"crypto/md5"
 	"fmt"
 	"log"
 	"strings"
 	"time"
-	"webox/cache"
 	"webox/util"
+
+	jsoniter "github.com/json-iterator/go"
 )
 
 /*JSSDK JSSDK */
@@ -18,6 +20,7 @@ type JSSDK struct {
 	subAppID    string
 	url         string
 	//CacheKey    func() string
+	cacheScope
 }
 
 /*NewJSSDK NewJSSDK */
@@ -26,6 +29,10 @@ func NewJSSDK(property *JSSDKProperty, options ...JSSDKOption) *JSSDK {
 		JSSDKProperty: property,
 	}
 	jssdk.parse(options...)
+	//选项顺序任意，解析完成后再将JSSDKCache设置的缓存传给未单独设置缓存的AccessToken
+	if jssdk.AccessToken != nil {
+		jssdk.AccessToken.inherit(&jssdk.cacheScope)
+	}
 	return jssdk
 }
 
@@ -193,26 +200,79 @@ func (obj *JSSDK) BuildConfig(url string, list ...string) *BuildConfig {
 
 // GetTicket ...
 func (obj *JSSDK) GetTicket(s string, refresh bool) string {
-	key := obj.getCacheKey()
-
-	if !refresh && cache.Has(key) {
-		if v, b := cache.Get(key).(string); b {
+	key := obj.getCacheKey(s)
 
-			return v
+	if !refresh {
+		if tr := obj.cachedTicket(key); tr != nil {
+			return tr.Ticket
 		}
 	}
 
-	tr, e := NewTicket(obj.AccessToken).GetTicketRes(s)
+	tr, e := obj.renewTicket(context.Background(), s)
 	if e != nil {
 		log.Println(e)
 		return ""
 	}
-
-	cache.Set(obj.getCacheKey(), tr.Ticket, time.Duration(tr.ExpiresIn-500)*time.Second)
 	return tr.Ticket
+}
 
+// renewTicket 请求新的ticket并缓存，access_token失效时Ticket.Get会刷新token并重放，并发获取同一ticket时只请求一次
+func (obj *JSSDK) renewTicket(ctx context.Context, s string) (*TicketRes, error) {
+	key := obj.getCacheKey(s)
+	v, e, _ := ticketFlight.Do(key, func() (any, error) {
+		tr, e := NewTicket(obj.AccessToken).getTicketRes(ctx, s)
+		if e != nil {
+			return nil, e
+		}
+		obj.setTicket(key, tr)
+		return tr, nil
+	})
+	if e != nil {
+		return nil, e
+	}
+	return v.(*TicketRes), nil
 }
 
+// setTicket 缓存ticket，未返回有效期时按TicketDefaultExpiresIn计算；
+// 与token相同，缓存时间预留AccessTokenSafeSeconds，有效期不足时按剩余有效期缓存
+func (obj *JSSDK) setTicket(key string, tr *TicketRes) {
+	if tr.ExpiresIn <= 0 {
+		tr.ExpiresIn = TicketDefaultExpiresIn
+	}
+	tr.Expiry = time.Now().Add(time.Duration(tr.ExpiresIn) * time.Second)
+	ttl := tr.Remaining() - AccessTokenSafeSeconds*time.Second
+	if ttl <= 0 {
+		ttl = tr.Remaining()
+	}
+	if ttl > 0 {
+		obj.getCache().Set(key, tr, ttl)
+	}
+}
+
+// cachedTicket 读取缓存中的ticket
+// 缓存值为*TicketRes，外部缓存无法保存类型时为其JSON，旧版本缓存的ticket字符串视为有效期未知
+func (obj *JSSDK) cachedTicket(key string) *TicketRes {
+	var tr *TicketRes
+	switch v := obj.getCache().Get(key).(type) {
+	case *TicketRes:
+		tr = v
+	case string:
+		tr = new(TicketRes)
+		if e := jsoniter.UnmarshalFromString(v, tr); e != nil {
+			tr = &TicketRes{Ticket: v}
+		}
+	default:
+		return nil
+	}
+	if tr.Ticket == "" {
+		return nil
+	}
+	return tr
+}
+
+// ticketFlight 合并进程内对同一ticket的并发请求
+var ticketFlight util.SingleFlight
+
 // getID ...
 func (obj *JSSDK) getID() string {
 	if obj.subAppID != "" {
@@ -221,9 +281,10 @@ func (obj *JSSDK) getID() string {
 	return obj.AppID
 }
 
-func (obj *JSSDK) getCacheKey() string {
+// getCacheKey jsapi与wx_card的api_ticket须分别缓存
+func (obj *JSSDK) getCacheKey(s string) string {
 	c := md5.Sum([]byte("jssdk." + obj.getID()))
-	return fmt.Sprintf("webox.jssdk.ticket.%x", c[:])
+	return obj.cacheKey("jssdk.ticket", s, fmt.Sprintf("%x", c[:]))
 }
 
 func (obj *JSSDK) parse(options ...JSSDKOption) {
@@ -1,6 +1,10 @@
 package model
 
-import "webox/util"
+import (
+	"errors"
+	"fmt"
+	"webox/util"
+)
 
 // CardScene ...
 type CardScene string
@@ -58,6 +62,18 @@ const (
 	CardTypeDiscount      CardType = "DISCOUNT"       //CardTypeDiscount DISCOUNT	折扣券类型。
 	CardTypeGift          CardType = "GIFT"           //CardTypeGift GIFT 兑换券类型。
 	CardTypeGeneralCoupon CardType = "GENERAL_COUPON" //CardTypeGeneralCoupon GENERAL_COUPON 优惠券类型。
+	CardTypeMemberCard    CardType = "MEMBER_CARD"    //CardTypeMemberCard MEMBER_CARD 会员卡类型。
+	CardTypeScenicTicket  CardType = "SCENIC_TICKET"  //CardTypeScenicTicket SCENIC_TICKET 景点门票类型。
+	CardTypeMovieTicket   CardType = "MOVIE_TICKET"   //CardTypeMovieTicket MOVIE_TICKET 电影票类型。
+	CardTypeBoardingPass  CardType = "BOARDING_PASS"  //CardTypeBoardingPass BOARDING_PASS 飞机票类型。
+	CardTypeMeetingTicket CardType = "MEETING_TICKET" //CardTypeMeetingTicket MEETING_TICKET 会议门票类型。
+)
+
+// CardDateTypeFixTimeRange 使用时间的类型，见CardDataInfo.Type
+const (
+	CardDateTypeFixTimeRange = "DATE_TYPE_FIX_TIME_RANGE" //CardDateTypeFixTimeRange 固定日期区间
+	CardDateTypeFixTerm      = "DATE_TYPE_FIX_TERM"       //CardDateTypeFixTerm 固定时长（自领取后按天算）
+	CardDateTypePermanent    = "DATE_TYPE_PERMANENT"      //CardDateTypePermanent 永久有效，仅会员卡可用
 )
 
 // CardDataInfo ...
@@ -71,7 +87,8 @@ type CardDataInfo struct {
 
 // CardSku ...
 type CardSku struct {
-	Quantity int `json:"quantity"` // quantity	是	int	100000	卡券库存的数量，上限为100000000。
+	Quantity      int `json:"quantity"`                 // quantity	是	int	100000	卡券库存的数量，上限为100000000。
+	TotalQuantity int `json:"total_quantity,omitempty"` // 卡券全部库存的数量，查询卡券详情时返回
 }
 
 // CardCodeType ...
@@ -129,6 +146,10 @@ type CardBaseInfo struct {
 	PromotionAppBrandUserName string       `json:"promotion_app_brand_user_name,omitempty"` //  promotion_app_brand_user_name	否	string（128）	gh_86a091e50ad4@app	卡券跳转的小程序的user_name，仅可跳转该 公众号绑定的小程序 。
 	PromotionAppBrandPass     string       `json:"promotion_app_brand_pass,omitempty"`      //  promotion_app_brand_pass	否	string（128）	API/cardPage	卡券跳转的小程序的path
 	Source                    string       `json:"source"`                                  //	"source": "大众点评"
+	ID                        string       `json:"id,omitempty"`                            // 卡券ID，查询卡券详情时返回
+	Status                    CardStatus   `json:"status,omitempty"`                        // 卡券的审核状态，查询卡券详情时返回
+	CreateTime                int64        `json:"create_time,omitempty"`                   // 创建时间，查询卡券详情时返回
+	UpdateTime                int64        `json:"update_time,omitempty"`                   // 更新时间，查询卡券详情时返回
 }
 
 // CardUseCondition ...
@@ -175,3 +196,515 @@ type OneCard struct {
 	CardType CardType `json:"card_type"`
 	data     util.Map
 }
+
+// CardInfo 各类型卡券共有的基本信息和高级信息
+type CardInfo struct {
+	BaseInfo     *CardBaseInfo     `json:"base_info"`
+	AdvancedInfo *CardAdvancedInfo `json:"advanced_info,omitempty"`
+}
+
+// CardGroupon 团购券
+type CardGroupon struct {
+	CardInfo
+	DealDetail string `json:"deal_detail"` // 团购券专用，团购详情
+}
+
+// CardCash 代金券
+type CardCash struct {
+	CardInfo
+	LeastCost  int `json:"least_cost,omitempty"` // 代金券专用，表示起用金额（单位为分），如果无起用门槛则填0
+	ReduceCost int `json:"reduce_cost"`          // 代金券专用，表示减免金额（单位为分）
+}
+
+// CardDiscount 折扣券
+type CardDiscount struct {
+	CardInfo
+	Discount int `json:"discount"` // 折扣券专用，表示打折额度（百分比），填30就是七折
+}
+
+// CardGift 兑换券
+type CardGift struct {
+	CardInfo
+	Gift string `json:"gift"` // 兑换券专用，填写兑换内容的名称
+}
+
+// CardGeneralCoupon 优惠券
+type CardGeneralCoupon struct {
+	CardInfo
+	DefaultDetail string `json:"default_detail"` // 优惠券专用，填写优惠详情
+}
+
+// CardMemberCustomField 会员卡自定义会员信息类目
+type CardMemberCustomField struct {
+	NameType string `json:"name_type,omitempty"` // 会员信息类目半自定义名称，如FIELD_NAME_TYPE_LEVEL等级
+	Name     string `json:"name,omitempty"`      // 会员信息类目自定义名称，与name_type二选一
+	URL      string `json:"url,omitempty"`       // 点击类目跳转外链url
+}
+
+// CardMemberCustomCell 会员卡自定义入口
+type CardMemberCustomCell struct {
+	Name string `json:"name"` // 入口名称
+	Tips string `json:"tips"` // 入口右侧提示语，6个汉字内
+	URL  string `json:"url"`  // 入口跳转链接
+}
+
+// CardMemberBonusRule 会员卡积分规则
+type CardMemberBonusRule struct {
+	CostMoneyUnit        int `json:"cost_money_unit,omitempty"`          // 消费金额，以分为单位
+	IncreaseBonus        int `json:"increase_bonus,omitempty"`           // 对应增加的积分
+	MaxIncreaseBonus     int `json:"max_increase_bonus,omitempty"`       // 用户单次可获取的积分上限
+	InitIncreaseBonus    int `json:"init_increase_bonus,omitempty"`      // 初始设置积分
+	CostBonusUnit        int `json:"cost_bonus_unit,omitempty"`          // 每使用积分
+	ReduceMoney          int `json:"reduce_money,omitempty"`             // 抵扣xx元，以分为单位
+	LeastMoneyToUseBonus int `json:"least_money_to_use_bonus,omitempty"` // 抵扣条件，满xx元（这里以分为单位）可用
+	MaxReduceBonus       int `json:"max_reduce_bonus,omitempty"`         // 抵扣条件，单笔最多使用xx积分
+}
+
+// CardMemberCard 会员卡
+type CardMemberCard struct {
+	CardInfo
+	BackgroundPicURL         string                 `json:"background_pic_url,omitempty"`           // 商家自定义会员卡背景图，像素大小控制在1000像素*600像素以下
+	Prerogative              string                 `json:"prerogative"`                            // 会员卡特权说明，限制1024汉字
+	AutoActivate             bool                   `json:"auto_activate,omitempty"`                // 设置为true时用户领取会员卡后系统自动将其激活，无需调用激活接口
+	WxActivate               bool                   `json:"wx_activate,omitempty"`                  // 设置为true时会员卡支持一键开卡，不允许同时传入activate_url字段
+	SupplyBonus              bool                   `json:"supply_bonus"`                           // 显示积分，填写true或false，如填写true，积分相关字段均为必填
+	BonusURL                 string                 `json:"bonus_url,omitempty"`                    // 设置跳转外链查看积分详情，仅适用于积分无法通过激活接口同步的情况下使用该字段
+	SupplyBalance            bool                   `json:"supply_balance"`                         // 是否支持储值，填写true或false，如填写true，储值相关字段均为必填
+	BalanceURL               string                 `json:"balance_url,omitempty"`                  // 设置跳转外链查看余额详情，仅适用于余额无法通过激活接口同步的情况下使用该字段
+	CustomField1             *CardMemberCustomField `json:"custom_field1,omitempty"`                // 自定义会员信息类目，会员卡激活后显示
+	CustomField2             *CardMemberCustomField `json:"custom_field2,omitempty"`                // 自定义会员信息类目，会员卡激活后显示
+	CustomField3             *CardMemberCustomField `json:"custom_field3,omitempty"`                // 自定义会员信息类目，会员卡激活后显示
+	BonusCleared             string                 `json:"bonus_cleared,omitempty"`                // 积分清零规则
+	BonusRules               string                 `json:"bonus_rules,omitempty"`                  // 积分规则
+	BalanceRules             string                 `json:"balance_rules,omitempty"`                // 储值说明
+	ActivateURL              string                 `json:"activate_url,omitempty"`                 // 激活会员卡的url
+	ActivateAppBrandUserName string                 `json:"activate_app_brand_user_name,omitempty"` // 激活会原卡url对应的小程序user_name，仅可跳转该公众号绑定的小程序
+	ActivateAppBrandPass     string                 `json:"activate_app_brand_pass,omitempty"`      // 激活会原卡url对应的小程序path
+	CustomCell1              *CardMemberCustomCell  `json:"custom_cell1,omitempty"`                 // 自定义会员信息类目，会员卡激活后显示
+	BonusRule                *CardMemberBonusRule   `json:"bonus_rule,omitempty"`                   // 积分规则
+	Discount                 int                    `json:"discount,omitempty"`                     // 折扣，该会员卡享受的折扣优惠，填10就是九折
+}
+
+// CardScenicTicket 景点门票
+type CardScenicTicket struct {
+	CardInfo
+	TicketClass string `json:"ticket_class,omitempty"` // 票类型，例如平日全票，套票等
+	GuideURL    string `json:"guide_url,omitempty"`    // 导览图url
+}
+
+// CardMovieTicket 电影票
+type CardMovieTicket struct {
+	CardInfo
+	Detail string `json:"detail"` // 电影票详情
+}
+
+// CardBoardingPass 飞机票
+type CardBoardingPass struct {
+	CardInfo
+	From          string `json:"from"`                     // 起点，上限为18个汉字
+	To            string `json:"to"`                       // 终点，上限为18个汉字
+	Flight        string `json:"flight"`                   // 航班
+	DepartureTime int64  `json:"departure_time,omitempty"` // 起飞时间，Unix时间戳格式
+	LandingTime   int64  `json:"landing_time,omitempty"`   // 降落时间，Unix时间戳格式
+	CheckInURL    string `json:"check_in_url,omitempty"`   // 在线值机的链接
+	Gate          string `json:"gate,omitempty"`           // 登机口
+	BoardingTime  int64  `json:"boarding_time,omitempty"`  // 登机时间，只显示“时分”不显示日期，按Unix时间戳格式填写
+	AirModel      string `json:"air_model,omitempty"`      // 机型，上限为8个汉字
+}
+
+// CardMeetingTicket 会议门票
+type CardMeetingTicket struct {
+	CardInfo
+	MeetingDetail string `json:"meeting_detail"`    // 会议详情
+	MapURL        string `json:"map_url,omitempty"` // 会场导览图
+}
+
+// Card 卡券，与CardType对应的字段中包含卡券的详细信息
+type Card struct {
+	CardType      CardType           `json:"card_type"`
+	Groupon       *CardGroupon       `json:"groupon,omitempty"`
+	Cash          *CardCash          `json:"cash,omitempty"`
+	Discount      *CardDiscount      `json:"discount,omitempty"`
+	Gift          *CardGift          `json:"gift,omitempty"`
+	GeneralCoupon *CardGeneralCoupon `json:"general_coupon,omitempty"`
+	MemberCard    *CardMemberCard    `json:"member_card,omitempty"`
+	ScenicTicket  *CardScenicTicket  `json:"scenic_ticket,omitempty"`
+	MovieTicket   *CardMovieTicket   `json:"movie_ticket,omitempty"`
+	BoardingPass  *CardBoardingPass  `json:"boarding_pass,omitempty"`
+	MeetingTicket *CardMeetingTicket `json:"meeting_ticket,omitempty"`
+}
+
+// Info 返回与CardType对应的基本信息和高级信息，类型字段为空时返回nil
+func (c *Card) Info() *CardInfo {
+	switch {
+	case c.CardType == CardTypeGroupon && c.Groupon != nil:
+		return &c.Groupon.CardInfo
+	case c.CardType == CardTypeCash && c.Cash != nil:
+		return &c.Cash.CardInfo
+	case c.CardType == CardTypeDiscount && c.Discount != nil:
+		return &c.Discount.CardInfo
+	case c.CardType == CardTypeGift && c.Gift != nil:
+		return &c.Gift.CardInfo
+	case c.CardType == CardTypeGeneralCoupon && c.GeneralCoupon != nil:
+		return &c.GeneralCoupon.CardInfo
+	case c.CardType == CardTypeMemberCard && c.MemberCard != nil:
+		return &c.MemberCard.CardInfo
+	case c.CardType == CardTypeScenicTicket && c.ScenicTicket != nil:
+		return &c.ScenicTicket.CardInfo
+	case c.CardType == CardTypeMovieTicket && c.MovieTicket != nil:
+		return &c.MovieTicket.CardInfo
+	case c.CardType == CardTypeBoardingPass && c.BoardingPass != nil:
+		return &c.BoardingPass.CardInfo
+	case c.CardType == CardTypeMeetingTicket && c.MeetingTicket != nil:
+		return &c.MeetingTicket.CardInfo
+	}
+	return nil
+}
+
+// WithAdvancedInfo 设置卡券高级信息
+func (c *Card) WithAdvancedInfo(info *CardAdvancedInfo) *Card {
+	if i := c.Info(); i != nil {
+		i.AdvancedInfo = info
+	}
+	return c
+}
+
+// ToMap implements util.MapAble
+func (c *Card) ToMap() util.Map {
+	m := make(util.Map)
+	if e := util.StructToMap(c, m); e != nil {
+		return nil
+	}
+	return m
+}
+
+// Validate 校验卡券的基本信息及各类型的必填字段
+func (c *Card) Validate() error {
+	info := c.Info()
+	if info == nil {
+		return fmt.Errorf("card: missing %s detail", c.CardType)
+	}
+	if info.BaseInfo == nil {
+		return errors.New("card: base_info is required")
+	}
+	errs := []error{info.BaseInfo.Validate()}
+	required := func(field, value string) {
+		if value == "" {
+			errs = append(errs, fmt.Errorf("card: %s is required", field))
+		}
+	}
+
+	switch c.CardType {
+	case CardTypeGroupon:
+		required("deal_detail", c.Groupon.DealDetail)
+	case CardTypeCash:
+		if c.Cash.ReduceCost <= 0 {
+			errs = append(errs, errors.New("card: reduce_cost must be positive"))
+		}
+		if c.Cash.LeastCost < 0 {
+			errs = append(errs, errors.New("card: least_cost must not be negative"))
+		}
+	case CardTypeDiscount:
+		if c.Discount.Discount < 1 || c.Discount.Discount > 99 {
+			errs = append(errs, fmt.Errorf("card: discount must be in [1, 99], got %d", c.Discount.Discount))
+		}
+	case CardTypeGift:
+		required("gift", c.Gift.Gift)
+	case CardTypeGeneralCoupon:
+		required("default_detail", c.GeneralCoupon.DefaultDetail)
+	case CardTypeMemberCard:
+		required("prerogative", c.MemberCard.Prerogative)
+		if c.MemberCard.WxActivate && c.MemberCard.ActivateURL != "" {
+			errs = append(errs, errors.New("card: wx_activate and activate_url are mutually exclusive"))
+		}
+	case CardTypeMovieTicket:
+		required("detail", c.MovieTicket.Detail)
+	case CardTypeBoardingPass:
+		required("from", c.BoardingPass.From)
+		required("to", c.BoardingPass.To)
+		required("flight", c.BoardingPass.Flight)
+	case CardTypeMeetingTicket:
+		required("meeting_detail", c.MeetingTicket.MeetingDetail)
+	}
+	if info.BaseInfo.DateInfo.Type == CardDateTypePermanent && c.CardType != CardTypeMemberCard {
+		errs = append(errs, errors.New("card: DATE_TYPE_PERMANENT is only available for member cards"))
+	}
+	return errors.Join(errs...)
+}
+
+// Validate 校验卡券基本信息的必填字段及长度限制，长度按UTF-8字节数计算
+func (b *CardBaseInfo) Validate() error {
+	var errs []error
+	check := func(field, value string, maxLen int, required bool) {
+		if required && value == "" {
+			errs = append(errs, fmt.Errorf("card: base_info.%s is required", field))
+		}
+		if len(value) > maxLen {
+			errs = append(errs, fmt.Errorf("card: base_info.%s must be at most %d bytes, got %d", field, maxLen, len(value)))
+		}
+	}
+	check("logo_url", b.LogoURL, 128, true)
+	check("brand_name", b.BrandName, 36, true)
+	check("code_type", string(b.CodeType), 16, true)
+	check("title", b.Title, 27, true)
+	check("color", b.Color, 16, true)
+	check("notice", b.Notice, 48, true)
+	check("description", b.Description, 3072, true)
+	check("service_phone", b.ServicePhone, 24, false)
+
+	switch d := b.DateInfo; d.Type {
+	case CardDateTypeFixTimeRange:
+		if d.BeginTimestamp <= 0 || d.EndTimestamp < d.BeginTimestamp {
+			errs = append(errs, errors.New("card: base_info.date_info requires begin_timestamp <= end_timestamp"))
+		}
+	case CardDateTypeFixTerm:
+		if d.FixedTerm <= 0 {
+			errs = append(errs, errors.New("card: base_info.date_info.fixed_term must be positive"))
+		}
+	case CardDateTypePermanent:
+	case "":
+		errs = append(errs, errors.New("card: base_info.date_info.type is required"))
+	default:
+		errs = append(errs, fmt.Errorf("card: unknown base_info.date_info.type %q", d.Type))
+	}
+
+	if b.Sku.Quantity < 0 || b.Sku.Quantity > CardSkuQuantityMax {
+		errs = append(errs, fmt.Errorf("card: base_info.sku.quantity must be in [0, %d]", CardSkuQuantityMax))
+	}
+	if b.GetCustomCodeMode != "" && b.Sku.Quantity != 0 {
+		errs = append(errs, errors.New("card: base_info.sku.quantity must be 0 with get_custom_code_mode"))
+	}
+	if b.UseAllLocations && len(b.LocationIDList) > 0 {
+		errs = append(errs, errors.New("card: base_info.use_all_locations and location_id_list are mutually exclusive"))
+	}
+	return errors.Join(errs...)
+}
+
+// CardSkuQuantityMax 卡券库存数量的上限
+const CardSkuQuantityMax = 100000000
+
+func newCard(card *Card) (*Card, error) {
+	if e := card.Validate(); e != nil {
+		return nil, e
+	}
+	return card, nil
+}
+
+// NewGrouponCard 团购券
+func NewGrouponCard(base *CardBaseInfo, dealDetail string) (*Card, error) {
+	return newCard(&Card{CardType: CardTypeGroupon, Groupon: &CardGroupon{
+		CardInfo:   CardInfo{BaseInfo: base},
+		DealDetail: dealDetail,
+	}})
+}
+
+// NewCashCard 代金券，金额单位为分
+func NewCashCard(base *CardBaseInfo, leastCost, reduceCost int) (*Card, error) {
+	return newCard(&Card{CardType: CardTypeCash, Cash: &CardCash{
+		CardInfo:   CardInfo{BaseInfo: base},
+		LeastCost:  leastCost,
+		ReduceCost: reduceCost,
+	}})
+}
+
+// NewDiscountCard 折扣券，discount为打折额度（百分比），填30就是七折
+func NewDiscountCard(base *CardBaseInfo, discount int) (*Card, error) {
+	return newCard(&Card{CardType: CardTypeDiscount, Discount: &CardDiscount{
+		CardInfo: CardInfo{BaseInfo: base},
+		Discount: discount,
+	}})
+}
+
+// NewGiftCard 兑换券
+func NewGiftCard(base *CardBaseInfo, gift string) (*Card, error) {
+	return newCard(&Card{CardType: CardTypeGift, Gift: &CardGift{
+		CardInfo: CardInfo{BaseInfo: base},
+		Gift:     gift,
+	}})
+}
+
+// NewGeneralCouponCard 优惠券
+func NewGeneralCouponCard(base *CardBaseInfo, defaultDetail string) (*Card, error) {
+	return newCard(&Card{CardType: CardTypeGeneralCoupon, GeneralCoupon: &CardGeneralCoupon{
+		CardInfo:      CardInfo{BaseInfo: base},
+		DefaultDetail: defaultDetail,
+	}})
+}
+
+// NewMemberCard 会员卡，base为nil时使用memberCard.BaseInfo
+func NewMemberCard(base *CardBaseInfo, memberCard *CardMemberCard) (*Card, error) {
+	if base != nil {
+		memberCard.BaseInfo = base
+	}
+	return newCard(&Card{CardType: CardTypeMemberCard, MemberCard: memberCard})
+}
+
+// NewScenicTicketCard 景点门票
+func NewScenicTicketCard(base *CardBaseInfo, ticket *CardScenicTicket) (*Card, error) {
+	if base != nil {
+		ticket.BaseInfo = base
+	}
+	return newCard(&Card{CardType: CardTypeScenicTicket, ScenicTicket: ticket})
+}
+
+// NewMovieTicketCard 电影票
+func NewMovieTicketCard(base *CardBaseInfo, detail string) (*Card, error) {
+	return newCard(&Card{CardType: CardTypeMovieTicket, MovieTicket: &CardMovieTicket{
+		CardInfo: CardInfo{BaseInfo: base},
+		Detail:   detail,
+	}})
+}
+
+// NewBoardingPassCard 飞机票
+func NewBoardingPassCard(base *CardBaseInfo, pass *CardBoardingPass) (*Card, error) {
+	if base != nil {
+		pass.BaseInfo = base
+	}
+	return newCard(&Card{CardType: CardTypeBoardingPass, BoardingPass: pass})
+}
+
+// NewMeetingTicketCard 会议门票
+func NewMeetingTicketCard(base *CardBaseInfo, meetingDetail, mapURL string) (*Card, error) {
+	return newCard(&Card{CardType: CardTypeMeetingTicket, MeetingTicket: &CardMeetingTicket{
+		CardInfo:      CardInfo{BaseInfo: base},
+		MeetingDetail: meetingDetail,
+		MapURL:        mapURL,
+	}})
+}
+
+// CardIDList 批量查询卡券列表的结果
+type CardIDList struct {
+	CardIDList []string `json:"card_id_list"` // 卡券ID列表
+	TotalNum   int      `json:"total_num"`    // 该商户名下卡券ID总数
+}
+
+// CardBatchGetMaxCount 批量查询卡券列表每次最多返回的数量
+const CardBatchGetMaxCount = 50
+
+// CardUserCardStatus 用户卡券的状态
+type CardUserCardStatus string
+
+// CardUserCardStatusNormal ...
+const (
+	CardUserCardStatusNormal      CardUserCardStatus = "NORMAL"       //正常
+	CardUserCardStatusConsumed    CardUserCardStatus = "CONSUMED"     //已核销
+	CardUserCardStatusExpire      CardUserCardStatus = "EXPIRE"       //已过期
+	CardUserCardStatusGifting     CardUserCardStatus = "GIFTING"      //转赠中
+	CardUserCardStatusGiftTimeout CardUserCardStatus = "GIFT_TIMEOUT" //转赠超时
+	CardUserCardStatusDelete      CardUserCardStatus = "DELETE"       //已删除
+	CardUserCardStatusUnavailable CardUserCardStatus = "UNAVAILABLE"  //已失效
+)
+
+// CardCodeCard 查询Code返回的卡券信息
+type CardCodeCard struct {
+	CardID    string `json:"card_id"`    // 卡券ID
+	BeginTime int64  `json:"begin_time"` // 起始使用时间
+	EndTime   int64  `json:"end_time"`   // 结束时间
+}
+
+// CardCodeInfo 查询Code的结果
+type CardCodeInfo struct {
+	Card           CardCodeCard       `json:"card"`
+	OpenID         string             `json:"openid"`              // 用户openid
+	CanConsume     bool               `json:"can_consume"`         // 是否可以核销，true为可以核销，false为不可核销
+	UserCardStatus CardUserCardStatus `json:"user_card_status"`    // 当前code对应卡券的状态
+	OuterStr       string             `json:"outer_str,omitempty"` // 领取场景值
+}
+
+// CardCodeConsumeResult 核销Code的结果
+type CardCodeConsumeResult struct {
+	Card struct {
+		CardID string `json:"card_id"` // 卡券ID
+	} `json:"card"`
+	OpenID string `json:"openid"` // 用户在该公众号内的唯一身份标识
+}
+
+// CardConsumeSource 核销来源
+type CardConsumeSource string
+
+// CardConsumeSourceAPI ...
+const (
+	CardConsumeSourceAPI          CardConsumeSource = "FROM_API"           //开发者API核销
+	CardConsumeSourceMobileHelper CardConsumeSource = "FROM_MOBILE_HELPER" //核销员微信核销
+	CardConsumeSourceSmallApp     CardConsumeSource = "FROM_SMALL_APP"     //小程序核销
+)
+
+// CardCheckEvent 卡券审核通过(card_pass_check)或未通过(card_not_pass_check)的事件推送
+type CardCheckEvent struct {
+	EventMessage
+	CardID       string `xml:"CardId"`       // 卡券ID
+	RefuseReason string `xml:"RefuseReason"` // 审核不通过原因
+}
+
+// UserGetCardEvent 用户领取卡券的事件推送
+type UserGetCardEvent struct {
+	EventMessage
+	CardID              string `xml:"CardId"`              // 卡券ID
+	IsGiveByFriend      int    `xml:"IsGiveByFriend"`      // 是否为转赠领取，1代表是，0代表否
+	FriendUserName      string `xml:"FriendUserName"`      // 转赠领取时为赠送方帐号
+	UserCardCode        string `xml:"UserCardCode"`        // code序列号
+	OldUserCardCode     string `xml:"OldUserCardCode"`     // 转赠前的code序列号
+	OuterID             int    `xml:"OuterId"`             // 领取场景值
+	OuterStr            string `xml:"OuterStr"`            // 领取场景值，用于领取渠道数据统计
+	IsRestoreMemberCard int    `xml:"IsRestoreMemberCard"` // 用户删除会员卡后可重新找回，1代表是，0代表否
+	IsRecommendByFriend int    `xml:"IsRecommendByFriend"` // 是否为朋友推荐，1代表是，0代表否
+	UnionID             string `xml:"UnionId"`             // 领券用户的UnionId
+}
+
+// UserGiftingCardEvent 用户转赠卡券的事件推送
+type UserGiftingCardEvent struct {
+	EventMessage
+	CardID         string `xml:"CardId"`         // 卡券ID
+	UserCardCode   string `xml:"UserCardCode"`   // code序列号
+	FriendUserName string `xml:"FriendUserName"` // 接收卡券用户的openid
+	IsReturnBack   int    `xml:"IsReturnBack"`   // 是否转赠退回，1代表是，0代表否
+	IsChatRoom     int    `xml:"IsChatRoom"`     // 是否是群转赠，1代表是，0代表否
+}
+
+// UserDelCardEvent 用户删除卡券的事件推送
+type UserDelCardEvent struct {
+	EventMessage
+	CardID       string `xml:"CardId"`       // 卡券ID
+	UserCardCode string `xml:"UserCardCode"` // code序列号
+}
+
+// UserConsumeCardEvent 卡券被核销的事件推送
+type UserConsumeCardEvent struct {
+	EventMessage
+	CardID        string            `xml:"CardId"`        // 卡券ID
+	UserCardCode  string            `xml:"UserCardCode"`  // code序列号
+	ConsumeSource CardConsumeSource `xml:"ConsumeSource"` // 核销来源
+	LocationName  string            `xml:"LocationName"`  // 门店名称
+	StaffOpenID   string            `xml:"StaffOpenId"`   // 核销该卡券核销员的openid
+	VerifyCode    string            `xml:"VerifyCode"`    // 自助核销时用户输入的验证码
+	RemarkAmount  string            `xml:"RemarkAmount"`  // 自助核销时用户输入的备注金额
+	OuterStr      string            `xml:"OuterStr"`      // 开发者发起核销时传入的自定义参数
+}
+
+// CardExt 添加卡券(wx.addCard)时cardList中的cardExt参数，须序列化为JSON字符串传入
+type CardExt struct {
+	Code                string `json:"code,omitempty"`                 // 自定义code的卡券必填
+	OpenID              string `json:"openid,omitempty"`               // 指定领取者的openid
+	Timestamp           string `json:"timestamp"`                      // 时间戳
+	NonceStr            string `json:"nonce_str"`                      // 随机字符串
+	FixedBeginTimestamp int64  `json:"fixed_begintimestamp,omitempty"` // 卡券在第三方系统的实际领取时间
+	OuterStr            string `json:"outer_str,omitempty"`            // 领取渠道参数，会在领取事件中返回
+	Signature           string `json:"signature"`                      // 签名
+}
+
+// CardAddItem wx.addCard中cardList的元素
+type CardAddItem struct {
+	CardID  string `json:"cardId"`
+	CardExt string `json:"cardExt"`
+}
+
+// CardChooseConfig 拉取适用卡券列表(wx.chooseCard)的参数
+type CardChooseConfig struct {
+	ShopID    string `json:"shopId"`    // 门店ID
+	CardType  string `json:"cardType"`  // 卡券类型
+	CardID    string `json:"cardId"`    // 卡券ID
+	Timestamp string `json:"timestamp"` // 时间戳
+	NonceStr  string `json:"nonceStr"`  // 随机字符串
+	SignType  string `json:"signType"`  // 签名方式，目前仅支持SHA1
+	CardSign  string `json:"cardSign"`  // 签名
+}
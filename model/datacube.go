@@ -0,0 +1,125 @@
+package model
+
+// DatacubeUserSummary 用户增减数据
+type DatacubeUserSummary struct {
+	RefDate    string `json:"ref_date"`
+	UserSource int    `json:"user_source"` // 用户的渠道
+	NewUser    int    `json:"new_user"`    // 新增的用户数量
+	CancelUser int    `json:"cancel_user"` // 取消关注的用户数量
+}
+
+// DatacubeUserCumulate 累计用户数据
+type DatacubeUserCumulate struct {
+	RefDate      string `json:"ref_date"`
+	CumulateUser int    `json:"cumulate_user"` // 总用户量
+}
+
+// DatacubeArticleRead 图文阅读、分享、收藏数据
+type DatacubeArticleRead struct {
+	IntPageReadUser  int `json:"int_page_read_user"`  // 图文页的阅读人数
+	IntPageReadCount int `json:"int_page_read_count"` // 图文页的阅读次数
+	OriPageReadUser  int `json:"ori_page_read_user"`  // 原文页的阅读人数
+	OriPageReadCount int `json:"ori_page_read_count"` // 原文页的阅读次数
+	ShareUser        int `json:"share_user"`          // 分享的人数
+	ShareCount       int `json:"share_count"`         // 分享的次数
+	AddToFavUser     int `json:"add_to_fav_user"`     // 收藏的人数
+	AddToFavCount    int `json:"add_to_fav_count"`    // 收藏的次数
+}
+
+// DatacubeArticleSummary 图文群发每日数据
+type DatacubeArticleSummary struct {
+	RefDate string `json:"ref_date"`
+	MsgID   string `json:"msgid"` // 图文消息id，由msgid（群发的消息id）和index（消息次序索引）组成
+	Title   string `json:"title"`
+	DatacubeArticleRead
+}
+
+// DatacubeArticleTotalDetail ...
+type DatacubeArticleTotalDetail struct {
+	StatDate   string `json:"stat_date"`   // 统计的日期
+	TargetUser int    `json:"target_user"` // 送达人数
+	DatacubeArticleRead
+}
+
+// DatacubeArticleTotal 图文群发总数据
+type DatacubeArticleTotal struct {
+	RefDate string                        `json:"ref_date"`
+	MsgID   string                        `json:"msgid"`
+	Title   string                        `json:"title"`
+	Details []*DatacubeArticleTotalDetail `json:"details"`
+}
+
+// DatacubeUserRead 图文统计数据
+type DatacubeUserRead struct {
+	RefDate    string `json:"ref_date"`
+	UserSource int    `json:"user_source"` // 用户从哪里进入来阅读该图文，99999999.全部
+	DatacubeArticleRead
+}
+
+// DatacubeUserReadHour 图文统计分时数据
+type DatacubeUserReadHour struct {
+	RefDate    string `json:"ref_date"`
+	RefHour    int    `json:"ref_hour"` // 数据的小时，包括从000到2300
+	UserSource int    `json:"user_source"`
+	DatacubeArticleRead
+}
+
+// DatacubeUserShare 图文分享转发数据
+type DatacubeUserShare struct {
+	RefDate    string `json:"ref_date"`
+	ShareScene int    `json:"share_scene"` // 分享的场景 1代表好友转发 2代表朋友圈 3代表腾讯微博 255代表其他
+	ShareCount int    `json:"share_count"`
+	ShareUser  int    `json:"share_user"`
+}
+
+// DatacubeUserShareHour 图文分享转发分时数据
+type DatacubeUserShareHour struct {
+	RefDate    string `json:"ref_date"`
+	RefHour    int    `json:"ref_hour"`
+	ShareScene int    `json:"share_scene"`
+	ShareCount int    `json:"share_count"`
+	ShareUser  int    `json:"share_user"`
+}
+
+// DatacubeUpstreamMsg 消息发送概况/周/月数据
+type DatacubeUpstreamMsg struct {
+	RefDate  string `json:"ref_date"`
+	MsgType  int    `json:"msg_type"`  // 消息类型，1代表文字 2代表图片 3代表语音 4代表视频 6代表第三方应用消息（链接消息）
+	MsgUser  int    `json:"msg_user"`  // 上行发送了（向公众号发送了）消息的用户数
+	MsgCount int    `json:"msg_count"` // 上行发送了消息的消息总数
+}
+
+// DatacubeUpstreamMsgHour 消息发送分时数据
+type DatacubeUpstreamMsgHour struct {
+	RefDate  string `json:"ref_date"`
+	RefHour  int    `json:"ref_hour"`
+	MsgType  int    `json:"msg_type"`
+	MsgUser  int    `json:"msg_user"`
+	MsgCount int    `json:"msg_count"`
+}
+
+// DatacubeUpstreamMsgDist 消息发送分布/周/月数据
+type DatacubeUpstreamMsgDist struct {
+	RefDate       string `json:"ref_date"`
+	CountInterval int    `json:"count_interval"` // 当日发送消息量分布的区间，0代表 “0”，1代表“1-5”，2代表“6-10”，3代表“10次以上”
+	MsgUser       int    `json:"msg_user"`
+}
+
+// DatacubeInterfaceSummary 接口分析数据
+type DatacubeInterfaceSummary struct {
+	RefDate       string `json:"ref_date"`
+	CallbackCount int    `json:"callback_count"`  // 通过服务器配置地址获得消息后，被动回复用户消息的次数
+	FailCount     int    `json:"fail_count"`      // 上述动作的失败次数
+	TotalTimeCost int    `json:"total_time_cost"` // 总耗时，除以callback_count即为平均耗时
+	MaxTimeCost   int    `json:"max_time_cost"`   // 最大耗时
+}
+
+// DatacubeInterfaceSummaryHour 接口分析分时数据
+type DatacubeInterfaceSummaryHour struct {
+	RefDate       string `json:"ref_date"`
+	RefHour       int    `json:"ref_hour"`
+	CallbackCount int    `json:"callback_count"`
+	FailCount     int    `json:"fail_count"`
+	TotalTimeCost int    `json:"total_time_cost"`
+	MaxTimeCost   int    `json:"max_time_cost"`
+}
@@ -0,0 +1,58 @@
+package model
+
+// DraftArticle 草稿箱/发布文章
+type DraftArticle struct {
+	Title              string `json:"title"`                           // 标题
+	Author             string `json:"author,omitempty"`                // 作者
+	Digest             string `json:"digest,omitempty"`                // 图文消息的摘要，仅有单图文消息才有摘要，多图文此处为空
+	Content            string `json:"content"`                         // 图文消息的具体内容，支持HTML标签，必须少于2万字符，小于1M
+	ContentSourceURL   string `json:"content_source_url"`              // 图文消息的原文地址，即点击“阅读原文”后的URL
+	ThumbMediaID       string `json:"thumb_media_id"`                  // 图文消息的封面图片素材id（必须是永久MediaID）
+	ShowCoverPic       int    `json:"show_cover_pic,omitempty"`        // 是否显示封面，0为false，即不显示，1为true，即显示
+	NeedOpenComment    uint32 `json:"need_open_comment,omitempty"`     // 是否打开评论，0不打开，1打开
+	OnlyFansCanComment uint32 `json:"only_fans_can_comment,omitempty"` // 是否粉丝才可评论，0所有人可评论，1粉丝才可评论
+	PicCrop2351        string `json:"pic_crop_235_1,omitempty"`        // 图文消息封面裁剪为2.35:1规格的坐标字段
+	PicCrop11          string `json:"pic_crop_1_1,omitempty"`          // 图文消息封面裁剪为1:1规格的坐标字段
+	URL                string `json:"url,omitempty"`                   // 草稿的临时链接/已发布图文的永久链接（仅返回）
+	ThumbURL           string `json:"thumb_url,omitempty"`             // 图文消息的封面url（仅返回）
+	IsDeleted          bool   `json:"is_deleted,omitempty"`            // 该图文是否被删除（仅发布接口返回）
+}
+
+// DraftNews ...
+type DraftNews struct {
+	NewsItem []*DraftArticle `json:"news_item"`
+}
+
+// DraftItem ...
+type DraftItem struct {
+	MediaID    string    `json:"media_id"`
+	Content    DraftNews `json:"content"`
+	UpdateTime int64     `json:"update_time"`
+}
+
+// DraftList ...
+type DraftList struct {
+	TotalCount int          `json:"total_count"` // 草稿素材的总数
+	ItemCount  int          `json:"item_count"`  // 本次调用获取的素材的数量
+	Item       []*DraftItem `json:"item"`
+}
+
+// FreePublishItem ...
+type FreePublishItem struct {
+	ArticleID  string    `json:"article_id"`
+	Content    DraftNews `json:"content"`
+	UpdateTime int64     `json:"update_time"`
+}
+
+// FreePublishList ...
+type FreePublishList struct {
+	TotalCount int                `json:"total_count"` // 成功发布素材的总数
+	ItemCount  int                `json:"item_count"`  // 本次调用获取的素材的数量
+	Item       []*FreePublishItem `json:"item"`
+}
+
+// FreePublishResult ...
+type FreePublishResult struct {
+	PublishID string `json:"publish_id"`  // 发布任务的id
+	MsgDataID int64  `json:"msg_data_id"` // 消息的数据ID
+}
@@ -0,0 +1,220 @@
+package model
+
+// KfInfo 客服基本信息
+type KfInfo struct {
+	KfAccount        string `json:"kf_account"`                   // 完整客服账号，格式为:账号前缀@公众号微信号
+	KfNick           string `json:"kf_nick"`                      // 客服昵称
+	KfID             string `json:"kf_id"`                        // 客服编号
+	KfHeadImgURL     string `json:"kf_headimgurl"`                // 客服头像
+	KfWx             string `json:"kf_wx,omitempty"`              // 如果客服帐号已绑定了客服人员微信号， 则此处显示微信号
+	InviteWx         string `json:"invite_wx,omitempty"`          // 如果客服帐号尚未绑定微信号，但是已经发起了一个绑定邀请， 则此处显示绑定邀请的微信号
+	InviteExpireTime int64  `json:"invite_expire_time,omitempty"` // 如果客服帐号尚未绑定微信号，但是已经发起过一个绑定邀请， 邀请的过期时间，为unix 时间戳
+	InviteStatus     string `json:"invite_status,omitempty"`      // 邀请的状态，有等待确认“waiting”，被拒绝“rejected”， 过期“expired”
+}
+
+// KfList ...
+type KfList struct {
+	KfList []*KfInfo `json:"kf_list"`
+}
+
+// KfOnlineInfo 在线客服信息
+type KfOnlineInfo struct {
+	KfAccount    string `json:"kf_account"`    // 完整客服帐号，格式为:帐号前缀@公众号微信号
+	Status       int    `json:"status"`        // 客服在线状态，目前为:1、web 在线
+	KfID         string `json:"kf_id"`         // 客服编号
+	AcceptedCase int    `json:"accepted_case"` // 客服当前正在接待的会话数
+}
+
+// KfOnlineList ...
+type KfOnlineList struct {
+	KfOnlineList []*KfOnlineInfo `json:"kf_online_list"`
+}
+
+// KfSession 客户会话状态
+type KfSession struct {
+	KfAccount  string `json:"kf_account,omitempty"` // 正在接待的客服，为空表示没有人在接待
+	OpenID     string `json:"openid,omitempty"`     // 粉丝的openid
+	CreateTime int64  `json:"createtime"`           // 会话接入的时间
+}
+
+// KfSessionList ...
+type KfSessionList struct {
+	SessionList []*KfSession `json:"sessionlist"`
+}
+
+// KfWaitCase 未接入会话列表
+type KfWaitCase struct {
+	Count        int          `json:"count"`        // 未接入会话数量
+	WaitCaseList []*KfSession `json:"waitcaselist"` // 未接入会话列表，最多返回100条数据，按照来访顺序
+}
+
+// KfMsgRecord 聊天记录
+type KfMsgRecord struct {
+	OpenID   string `json:"openid"`   // 用户标识
+	OperCode int    `json:"opercode"` // 操作码，2002（客服发送信息），2003（客服接收消息）
+	Text     string `json:"text"`     // 聊天记录
+	Time     int64  `json:"time"`     // 操作时间，unix时间戳
+	Worker   string `json:"worker"`   // 完整客服帐号，格式为:帐号前缀@公众号微信号
+}
+
+// KfMsgRecordList ...
+type KfMsgRecordList struct {
+	RecordList []*KfMsgRecord `json:"recordlist"`
+	Number     int            `json:"number"` // 本次返回的记录条数
+	MsgID      int64          `json:"msgid"`  // 下一页请求的msgid
+}
+
+// CustomText ...
+type CustomText struct {
+	Content string `json:"content"`
+}
+
+// CustomMedia ...
+type CustomMedia struct {
+	MediaID string `json:"media_id"`
+}
+
+// CustomVideo ...
+type CustomVideo struct {
+	MediaID      string `json:"media_id"`
+	ThumbMediaID string `json:"thumb_media_id"`
+	Title        string `json:"title,omitempty"`
+	Description  string `json:"description,omitempty"`
+}
+
+// CustomMusic ...
+type CustomMusic struct {
+	Title        string `json:"title,omitempty"`
+	Description  string `json:"description,omitempty"`
+	MusicURL     string `json:"musicurl"`
+	HQMusicURL   string `json:"hqmusicurl"`
+	ThumbMediaID string `json:"thumb_media_id"`
+}
+
+// CustomArticle 图文消息（点击跳转到外链），图文消息条数限制在1条以内
+type CustomArticle struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	URL         string `json:"url"`
+	PicURL      string `json:"picurl"`
+}
+
+// CustomNews ...
+type CustomNews struct {
+	Articles []*CustomArticle `json:"articles"`
+}
+
+// CustomArticleID 发送已发布的图文消息
+type CustomArticleID struct {
+	ArticleID string `json:"article_id"`
+}
+
+// CustomMenuItem ...
+type CustomMenuItem struct {
+	ID      string `json:"id"`
+	Content string `json:"content"`
+}
+
+// CustomMsgMenu 菜单消息
+type CustomMsgMenu struct {
+	HeadContent string            `json:"head_content"`
+	List        []*CustomMenuItem `json:"list"`
+	TailContent string            `json:"tail_content"`
+}
+
+// CustomWxCard ...
+type CustomWxCard struct {
+	CardID string `json:"card_id"`
+}
+
+// CustomMiniProgramPage ...
+type CustomMiniProgramPage struct {
+	Title        string `json:"title"`
+	AppID        string `json:"appid"`
+	PagePath     string `json:"pagepath"`
+	ThumbMediaID string `json:"thumb_media_id"`
+}
+
+// CustomService 以某个客服帐号来发消息
+type CustomService struct {
+	KfAccount string `json:"kf_account"`
+}
+
+// CustomMessage 客服消息
+type CustomMessage struct {
+	ToUser          string                 `json:"touser"`
+	MsgType         MsgType                `json:"msgtype"`
+	Text            *CustomText            `json:"text,omitempty"`
+	Image           *CustomMedia           `json:"image,omitempty"`
+	Voice           *CustomMedia           `json:"voice,omitempty"`
+	Video           *CustomVideo           `json:"video,omitempty"`
+	Music           *CustomMusic           `json:"music,omitempty"`
+	News            *CustomNews            `json:"news,omitempty"`
+	MPNews          *CustomMedia           `json:"mpnews,omitempty"`
+	MPNewsArticle   *CustomArticleID       `json:"mpnewsarticle,omitempty"`
+	MsgMenu         *CustomMsgMenu         `json:"msgmenu,omitempty"`
+	WxCard          *CustomWxCard          `json:"wxcard,omitempty"`
+	MiniProgramPage *CustomMiniProgramPage `json:"miniprogrampage,omitempty"`
+	CustomService   *CustomService         `json:"customservice,omitempty"`
+}
+
+// NewCustomText 文本消息
+func NewCustomText(toUser, content string) *CustomMessage {
+	return &CustomMessage{ToUser: toUser, MsgType: MsgTypeText, Text: &CustomText{Content: content}}
+}
+
+// NewCustomImage 图片消息
+func NewCustomImage(toUser, mediaID string) *CustomMessage {
+	return &CustomMessage{ToUser: toUser, MsgType: MsgTypeImage, Image: &CustomMedia{MediaID: mediaID}}
+}
+
+// NewCustomVoice 语音消息
+func NewCustomVoice(toUser, mediaID string) *CustomMessage {
+	return &CustomMessage{ToUser: toUser, MsgType: MsgTypeVoice, Voice: &CustomMedia{MediaID: mediaID}}
+}
+
+// NewCustomVideo 视频消息
+func NewCustomVideo(toUser string, video *CustomVideo) *CustomMessage {
+	return &CustomMessage{ToUser: toUser, MsgType: MsgTypeVideo, Video: video}
+}
+
+// NewCustomMusic 音乐消息
+func NewCustomMusic(toUser string, music *CustomMusic) *CustomMessage {
+	return &CustomMessage{ToUser: toUser, MsgType: MsgTypeMusic, Music: music}
+}
+
+// NewCustomNews 图文消息（点击跳转到外链）
+func NewCustomNews(toUser string, article *CustomArticle) *CustomMessage {
+	return &CustomMessage{ToUser: toUser, MsgType: MsgTypeNews, News: &CustomNews{Articles: []*CustomArticle{article}}}
+}
+
+// NewCustomMPNews 图文消息（点击跳转到图文消息页面）
+func NewCustomMPNews(toUser, mediaID string) *CustomMessage {
+	return &CustomMessage{ToUser: toUser, MsgType: MsgTypeMpnews, MPNews: &CustomMedia{MediaID: mediaID}}
+}
+
+// NewCustomMPNewsArticle 图文消息（已发布的文章）
+func NewCustomMPNewsArticle(toUser, articleID string) *CustomMessage {
+	return &CustomMessage{ToUser: toUser, MsgType: MsgTypeMpnewsArticle, MPNewsArticle: &CustomArticleID{ArticleID: articleID}}
+}
+
+// NewCustomMsgMenu 菜单消息
+func NewCustomMsgMenu(toUser string, menu *CustomMsgMenu) *CustomMessage {
+	return &CustomMessage{ToUser: toUser, MsgType: MsgTypeMsgMenu, MsgMenu: menu}
+}
+
+// NewCustomWxCard 卡券消息
+func NewCustomWxCard(toUser, cardID string) *CustomMessage {
+	return &CustomMessage{ToUser: toUser, MsgType: MsgTypeWxCard, WxCard: &CustomWxCard{CardID: cardID}}
+}
+
+// NewCustomMiniProgramPage 小程序卡片
+func NewCustomMiniProgramPage(toUser string, page *CustomMiniProgramPage) *CustomMessage {
+	return &CustomMessage{ToUser: toUser, MsgType: MsgTypeMiniprogrampage, MiniProgramPage: page}
+}
+
+// WithKfAccount 以某个客服帐号来发消息（在微信6.0.2及以上版本中显示自定义头像）
+func (m *CustomMessage) WithKfAccount(account string) *CustomMessage {
+	m.CustomService = &CustomService{KfAccount: account}
+	return m
+}
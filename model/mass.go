@@ -0,0 +1,209 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+)
+
+// MassOpenIDLimit 根据OpenID列表群发时每次调用的最大OpenID数量
+const MassOpenIDLimit = 10000
+
+// MassImages 群发图片消息，支持多张图片
+type MassImages struct {
+	MediaIDs           []string `json:"media_ids"`
+	Recommend          string   `json:"recommend,omitempty"`             // 推荐语，不填则默认为“分享图片”
+	NeedOpenComment    int      `json:"need_open_comment,omitempty"`     // 是否打开评论，0不打开，1打开
+	OnlyFansCanComment int      `json:"only_fans_can_comment,omitempty"` // 是否粉丝才可评论，0所有人可评论，1粉丝才可评论
+}
+
+// MassMessage 群发消息内容，发送对象由MassTarget指定
+type MassMessage struct {
+	MsgType           MsgType       `json:"msgtype"`
+	MPNews            *CustomMedia  `json:"mpnews,omitempty"`
+	Text              *CustomText   `json:"text,omitempty"`
+	Voice             *CustomMedia  `json:"voice,omitempty"`
+	Images            *MassImages   `json:"images,omitempty"`
+	MPVideo           *CustomMedia  `json:"mpvideo,omitempty"`
+	WxCard            *CustomWxCard `json:"wxcard,omitempty"`
+	SendIgnoreReprint int           `json:"send_ignore_reprint"`   // 图文消息被判定为转载时，是否继续群发。 1为继续群发（转载），0为停止群发
+	ClientMsgID       string        `json:"clientmsgid,omitempty"` // 开发者侧群发msgid，长度限制64字节，用于避免重复推送
+}
+
+// NewMassMPNews 图文消息
+func NewMassMPNews(mediaID string) *MassMessage {
+	return &MassMessage{MsgType: MsgTypeMpnews, MPNews: &CustomMedia{MediaID: mediaID}}
+}
+
+// NewMassText 文本消息
+func NewMassText(content string) *MassMessage {
+	return &MassMessage{MsgType: MsgTypeText, Text: &CustomText{Content: content}}
+}
+
+// NewMassVoice 语音/音频消息
+func NewMassVoice(mediaID string) *MassMessage {
+	return &MassMessage{MsgType: MsgTypeVoice, Voice: &CustomMedia{MediaID: mediaID}}
+}
+
+// NewMassImage 图片消息
+func NewMassImage(images *MassImages) *MassMessage {
+	return &MassMessage{MsgType: MsgTypeImage, Images: images}
+}
+
+// NewMassMPVideo 视频消息，mediaID需通过视频群发上传接口获得
+func NewMassMPVideo(mediaID string) *MassMessage {
+	return &MassMessage{MsgType: MsgTypeMpvideo, MPVideo: &CustomMedia{MediaID: mediaID}}
+}
+
+// NewMassWxCard 卡券消息
+func NewMassWxCard(cardID string) *MassMessage {
+	return &MassMessage{MsgType: MsgTypeWxCard, WxCard: &CustomWxCard{CardID: cardID}}
+}
+
+// WithClientMsgID 设置clientmsgid，使用相同clientmsgid重复推送时将直接返回之前的群发结果
+func (m *MassMessage) WithClientMsgID(id string) *MassMessage {
+	m.ClientMsgID = id
+	return m
+}
+
+// WithSendIgnoreReprint 图文消息被判定为转载时是否继续群发
+func (m *MassMessage) WithSendIgnoreReprint(ignore bool) *MassMessage {
+	m.SendIgnoreReprint = 0
+	if ignore {
+		m.SendIgnoreReprint = 1
+	}
+	return m
+}
+
+// MassTargetKind 群发对象类型
+type MassTargetKind int
+
+// MassTargetAll ...
+const (
+	MassTargetAll     MassTargetKind = iota + 1 // 全部用户，调用sendall接口
+	MassTargetTag                               // 指定标签，调用sendall接口
+	MassTargetOpenIDs                           // OpenID列表，调用send接口
+)
+
+// MassTarget 群发对象：全部用户、标签或OpenID列表，应通过MassToAll/MassToTag/MassToOpenIDs创建
+type MassTarget struct {
+	Kind    MassTargetKind
+	TagID   int
+	OpenIDs []string
+}
+
+// MassToAll 发送给全部用户
+func MassToAll() *MassTarget {
+	return &MassTarget{Kind: MassTargetAll}
+}
+
+// MassToTag 发送给指定标签的用户
+func MassToTag(tagID int) *MassTarget {
+	return &MassTarget{Kind: MassTargetTag, TagID: tagID}
+}
+
+// MassToOpenIDs 发送给OpenID列表中的用户
+func MassToOpenIDs(openids ...string) *MassTarget {
+	return &MassTarget{Kind: MassTargetOpenIDs, OpenIDs: openids}
+}
+
+// Validate 校验群发对象，OpenID列表为空时返回错误，避免误发给全部用户或标签；
+// 按OpenID列表群发要求至少两个OpenID，只有一个时返回错误，单个用户可使用预览或客服消息
+func (t *MassTarget) Validate() error {
+	if t == nil {
+		return errors.New("mass: target is required")
+	}
+	switch t.Kind {
+	case MassTargetAll, MassTargetTag:
+		return nil
+	case MassTargetOpenIDs:
+		if len(t.OpenIDs) == 0 {
+			return errors.New("mass: openid list is empty")
+		}
+		if len(t.OpenIDs) == 1 {
+			return errors.New("mass: openid list requires at least 2 openids")
+		}
+		return nil
+	}
+	return fmt.Errorf("mass: unknown target kind %d", t.Kind)
+}
+
+// Chunks 将OpenID列表按每次调用的上限拆分，并避免最后一组只剩一个OpenID（接口要求至少两个）
+func (t *MassTarget) Chunks() [][]string {
+	var chunks [][]string
+	ids := t.OpenIDs
+	for len(ids) > MassOpenIDLimit {
+		chunks = append(chunks, ids[:MassOpenIDLimit])
+		ids = ids[MassOpenIDLimit:]
+	}
+	if len(ids) == 1 && len(chunks) > 0 {
+		last := chunks[len(chunks)-1]
+		chunks[len(chunks)-1] = last[:len(last)-1]
+		ids = append([]string{last[len(last)-1]}, ids...)
+	}
+	if len(ids) > 0 {
+		chunks = append(chunks, ids)
+	}
+	return chunks
+}
+
+// MassResult 群发结果
+type MassResult struct {
+	MsgID     int64 `json:"msg_id"`      // 消息发送任务的ID
+	MsgDataID int64 `json:"msg_data_id"` // 消息的数据ID，该字段只有在群发图文消息时，才会出现
+}
+
+// MassStatus 群发消息发送状态
+type MassStatus struct {
+	MsgID     int64  `json:"msg_id"`
+	MsgStatus string `json:"msg_status"` // 消息发送后的状态，SEND_SUCCESS表示发送成功，SENDING表示发送中，SEND_FAIL表示发送失败，DELETE表示已删除
+}
+
+// MassSpeed 群发速度
+type MassSpeed struct {
+	Speed     int `json:"speed"`     // 群发速度的级别，0:80w/分钟 1:60w/分钟 2:45w/分钟 3:30w/分钟 4:10w/分钟
+	RealSpeed int `json:"realspeed"` // 群发速度的真实值 单位:万/分钟
+}
+
+// MassCopyrightResult 单篇图文的原创校验结果
+type MassCopyrightResult struct {
+	ArticleIdx            int    `xml:"ArticleIdx"`            // 群发文章的序号，从1开始
+	UserDeclareState      int    `xml:"UserDeclareState"`      // 用户声明文章的状态
+	AuditState            int    `xml:"AuditState"`            // 系统校验的状态
+	OriginalArticleURL    string `xml:"OriginalArticleUrl"`    // 相似原创文的url
+	OriginalArticleType   int    `xml:"OriginalArticleType"`   // 相似原创文的类型
+	CanReprint            int    `xml:"CanReprint"`            // 是否能转载
+	NeedReplaceContent    int    `xml:"NeedReplaceContent"`    // 是否需要替换成原创文内容
+	NeedShowReprintSource int    `xml:"NeedShowReprintSource"` // 是否需要注明转载来源
+}
+
+// MassCopyrightCheckResult 原创校验结果
+type MassCopyrightCheckResult struct {
+	Count      int                    `xml:"Count"`
+	ResultList []*MassCopyrightResult `xml:"ResultList>item"`
+	CheckState int                    `xml:"CheckState"` // 整体校验结果 1-未被判为转载，可以群发，2-被判为转载，可以群发，3-被判为转载，不能群发
+}
+
+// MassArticleURL ...
+type MassArticleURL struct {
+	ArticleIdx int    `xml:"ArticleIdx"`
+	ArticleURL string `xml:"ArticleUrl"`
+}
+
+// MassArticleURLResult 群发文章的url
+type MassArticleURLResult struct {
+	Count      int               `xml:"Count"`
+	ResultList []*MassArticleURL `xml:"ResultList>item"`
+}
+
+// MassSendJobFinishEvent 事件推送群发结果
+type MassSendJobFinishEvent struct {
+	EventMessage
+	MsgID                int64                    `xml:"MsgID"`       // 群发的消息ID
+	Status               string                   `xml:"Status"`      // 群发的结果，为“send success”或“send fail”或“err(num)”
+	TotalCount           int                      `xml:"TotalCount"`  // 标签下粉丝数；或者openid_list中的粉丝数
+	FilterCount          int                      `xml:"FilterCount"` // 过滤后准备发送的粉丝数
+	SentCount            int                      `xml:"SentCount"`   // 发送成功的粉丝数
+	ErrorCount           int                      `xml:"ErrorCount"`  // 发送失败的粉丝数
+	CopyrightCheckResult MassCopyrightCheckResult `xml:"CopyrightCheckResult"`
+	ArticleURLResult     MassArticleURLResult     `xml:"ArticleUrlResult"`
+}
@@ -0,0 +1,33 @@
+package model
+
+// MaterialCount 素材总数
+type MaterialCount struct {
+	VoiceCount int `json:"voice_count"`
+	VideoCount int `json:"video_count"`
+	ImageCount int `json:"image_count"`
+	NewsCount  int `json:"news_count"`
+}
+
+// MaterialItem 素材列表项，图文素材只有Content，其他类型素材只有Name和URL
+type MaterialItem struct {
+	MediaID    string     `json:"media_id"`
+	Name       string     `json:"name,omitempty"`
+	URL        string     `json:"url,omitempty"`
+	UpdateTime int64      `json:"update_time"`
+	Content    *DraftNews `json:"content,omitempty"`
+}
+
+// MaterialList 素材列表
+type MaterialList struct {
+	TotalCount int             `json:"total_count"` // 该类型的素材的总数
+	ItemCount  int             `json:"item_count"`  // 本次调用获取的素材的数量
+	Item       []*MaterialItem `json:"item"`
+}
+
+// MaterialContent 图文或视频素材的内容，其他类型的素材为二进制文件
+type MaterialContent struct {
+	NewsItem    []*DraftArticle `json:"news_item,omitempty"`
+	Title       string          `json:"title,omitempty"`
+	Description string          `json:"description,omitempty"`
+	DownURL     string          `json:"down_url,omitempty"`
+}
@@ -0,0 +1,212 @@
+package model
+
+// MemberCardActivate 接口激活会员卡的参数
+type MemberCardActivate struct {
+	MembershipNumber      string `json:"membership_number"`                  // 会员卡编号，由开发者填入，作为序列号显示在用户的卡包里。可与Code码保持等值
+	Code                  string `json:"code"`                               // 领取会员卡用户获得的code
+	CardID                string `json:"card_id,omitempty"`                  // 卡券ID，自定义code的会员卡必填card_id，非自定义code的会员卡不必填
+	BackgroundPicURL      string `json:"background_pic_url,omitempty"`       // 商家自定义会员卡背景图，须先调用上传图片接口将背景图上传至CDN
+	ActivateBeginTime     int64  `json:"activate_begin_time,omitempty"`      // 激活后的有效起始时间，若不填写默认以创建时的data_info为准，Unix时间戳格式
+	ActivateEndTime       int64  `json:"activate_end_time,omitempty"`        // 激活后的有效截至时间，若不填写默认以创建时的data_info为准，Unix时间戳格式
+	InitBonus             int    `json:"init_bonus,omitempty"`               // 初始积分，不填为0
+	InitBonusRecord       string `json:"init_bonus_record,omitempty"`        // 积分同步说明
+	InitBalance           int    `json:"init_balance,omitempty"`             // 初始余额，不填为0，单位为分
+	InitCustomFieldValue1 string `json:"init_custom_field_value1,omitempty"` // 创建时字段custom_field1定义类型的初始值，限制为4个汉字，12字节
+	InitCustomFieldValue2 string `json:"init_custom_field_value2,omitempty"` // 创建时字段custom_field2定义类型的初始值，限制为4个汉字，12字节
+	InitCustomFieldValue3 string `json:"init_custom_field_value3,omitempty"` // 创建时字段custom_field3定义类型的初始值，限制为4个汉字，12字节
+}
+
+// MemberCardFormField 会员卡激活时的常用字段
+type MemberCardFormField string
+
+// MemberCardFormFieldMobile ...
+const (
+	MemberCardFormFieldMobile              MemberCardFormField = "USER_FORM_INFO_FLAG_MOBILE"            //手机号
+	MemberCardFormFieldSex                 MemberCardFormField = "USER_FORM_INFO_FLAG_SEX"               //性别
+	MemberCardFormFieldName                MemberCardFormField = "USER_FORM_INFO_FLAG_NAME"              //姓名
+	MemberCardFormFieldBirthday            MemberCardFormField = "USER_FORM_INFO_FLAG_BIRTHDAY"          //生日
+	MemberCardFormFieldIDCard              MemberCardFormField = "USER_FORM_INFO_FLAG_IDCARD"            //身份证
+	MemberCardFormFieldEmail               MemberCardFormField = "USER_FORM_INFO_FLAG_EMAIL"             //邮箱
+	MemberCardFormFieldLocation            MemberCardFormField = "USER_FORM_INFO_FLAG_LOCATION"          //详细地址
+	MemberCardFormFieldEducationBackground MemberCardFormField = "USER_FORM_INFO_FLAG_EDUCATION_BACKGRO" //教育背景
+	MemberCardFormFieldIndustry            MemberCardFormField = "USER_FORM_INFO_FLAG_INDUSTRY"          //行业
+	MemberCardFormFieldIncome              MemberCardFormField = "USER_FORM_INFO_FLAG_INCOME"            //收入
+	MemberCardFormFieldHabit               MemberCardFormField = "USER_FORM_INFO_FLAG_HABIT"             //兴趣爱好
+)
+
+// MemberCardRichFieldType 富文本字段的类型
+type MemberCardRichFieldType string
+
+// MemberCardRichFieldRadio ...
+const (
+	MemberCardRichFieldRadio    MemberCardRichFieldType = "FORM_FIELD_RADIO"     //自定义单选
+	MemberCardRichFieldSelect   MemberCardRichFieldType = "FORM_FIELD_SELECT"    //自定义选择项
+	MemberCardRichFieldCheckBox MemberCardRichFieldType = "FORM_FIELD_CHECK_BOX" //自定义多选
+)
+
+// MemberCardRichField 激活表单中的富文本字段
+type MemberCardRichField struct {
+	Type   MemberCardRichFieldType `json:"type"`
+	Name   string                  `json:"name"`   // 字段名
+	Values []string                `json:"values"` // 选择项
+}
+
+// MemberCardForm 激活表单的字段
+type MemberCardForm struct {
+	CanModify         bool                  `json:"can_modify"`                     // 当前结构（required_form或者optional_form）内的字段是否允许用户激活后再次修改
+	CommonFieldIDList []MemberCardFormField `json:"common_field_id_list,omitempty"` // 微信格式化的选项类型
+	CustomFieldList   []string              `json:"custom_field_list,omitempty"`    // 自定义选项名称，开发者可以分别在必填和选填中至多定义五个自定义选项
+	RichFieldList     []MemberCardRichField `json:"rich_field_list,omitempty"`      // 自定义富文本类型，包含以下三个字段，开发者可以分别在必填和选填中至多定义五个自定义选项
+}
+
+// MemberCardFormLink 激活表单中的跳转链接
+type MemberCardFormLink struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+// MemberCardActivateUserForm 一键激活的开卡字段
+type MemberCardActivateUserForm struct {
+	CardID           string              `json:"card_id"`
+	ServiceStatement *MemberCardFormLink `json:"service_statement,omitempty"` // 服务声明，用于放置商户会员卡守则
+	BindOldCard      *MemberCardFormLink `json:"bind_old_card,omitempty"`     // 绑定老会员链接
+	RequiredForm     *MemberCardForm     `json:"required_form,omitempty"`     // 会员卡激活时的必填选项
+	OptionalForm     *MemberCardForm     `json:"optional_form,omitempty"`     // 会员卡激活时的选填项
+}
+
+// MemberCardField 用户填写的字段，多选类型的自定义字段值在ValueList中
+type MemberCardField struct {
+	Name      string   `json:"name"`
+	Value     string   `json:"value"`
+	ValueList []string `json:"value_list,omitempty"`
+}
+
+// MemberCardFormInfo 用户填写的开卡信息
+type MemberCardFormInfo struct {
+	CommonFieldList []MemberCardField `json:"common_field_list"` // 微信格式化的选项类型
+	CustomFieldList []MemberCardField `json:"custom_field_list"` // 自定义选项
+}
+
+// Common 返回常用字段的值
+func (i *MemberCardFormInfo) Common(field MemberCardFormField) string {
+	for _, f := range i.CommonFieldList {
+		if f.Name == string(field) {
+			return f.Value
+		}
+	}
+	return ""
+}
+
+// Custom 返回自定义字段，不存在时返回nil
+func (i *MemberCardFormInfo) Custom(name string) *MemberCardField {
+	for k := range i.CustomFieldList {
+		if i.CustomFieldList[k].Name == name {
+			return &i.CustomFieldList[k]
+		}
+	}
+	return nil
+}
+
+// MemberCardUserInfo 会员信息
+type MemberCardUserInfo struct {
+	OpenID           string             `json:"openid"`
+	Nickname         string             `json:"nickname"`          // 用户昵称
+	MembershipNumber string             `json:"membership_number"` // 用户会员卡号
+	Bonus            int                `json:"bonus"`             // 积分信息
+	Balance          int                `json:"balance"`           // 余额信息
+	Sex              string             `json:"sex"`               // 用户性别
+	UserInfo         MemberCardFormInfo `json:"user_info"`         // 会员信息
+	UserCardStatus   CardUserCardStatus `json:"user_card_status"`  // 当前用户会员卡状态
+	HasActive        bool               `json:"has_active"`        // 该卡是否已经被激活，true表示已经被激活，false表示未被激活
+}
+
+// MemberCardNotifyOptional 更新会员信息时是否推送消息，设置后所有字段均会传入，不再使用默认值
+type MemberCardNotifyOptional struct {
+	IsNotifyBonus        bool `json:"is_notify_bonus"`         // 积分变动时是否触发系统模板消息，默认为true
+	IsNotifyBalance      bool `json:"is_notify_balance"`       // 余额变动时是否触发系统模板消息，默认为true
+	IsNotifyCustomField1 bool `json:"is_notify_custom_field1"` // 自定义group1变动时是否触发系统模板消息，默认为false
+	IsNotifyCustomField2 bool `json:"is_notify_custom_field2"` // 自定义group2变动时是否触发系统模板消息，默认为false
+	IsNotifyCustomField3 bool `json:"is_notify_custom_field3"` // 自定义group3变动时是否触发系统模板消息，默认为false
+}
+
+// MemberCardUpdateUser 更新会员信息的参数，积分和余额可以设置全量值或变动值
+type MemberCardUpdateUser struct {
+	Code              string                    `json:"code"`                          // 卡券Code码
+	CardID            string                    `json:"card_id"`                       // 卡券ID
+	BackgroundPicURL  string                    `json:"background_pic_url,omitempty"`  // 支持商家激活时针对单个会员卡分配自定义的会员卡背景
+	Bonus             *int                      `json:"bonus,omitempty"`               // 需要设置的积分全量值，传入的数值会直接显示
+	AddBonus          int                       `json:"add_bonus,omitempty"`           // 本次积分变动值，传负数代表减少
+	RecordBonus       string                    `json:"record_bonus,omitempty"`        // 商家自定义积分消耗记录，不超过14个汉字
+	Balance           *int                      `json:"balance,omitempty"`             // 需要设置的余额全量值，传入的数值会直接显示在卡面，单位为分
+	AddBalance        int                       `json:"add_balance,omitempty"`         // 本次余额变动值，传负数代表减少，单位为分
+	RecordBalance     string                    `json:"record_balance,omitempty"`      // 商家自定义金额消耗记录，不超过14个汉字
+	CustomFieldValue1 string                    `json:"custom_field_value1,omitempty"` // 创建时字段custom_field1定义类型的最新数值，限制为4个汉字，12字节
+	CustomFieldValue2 string                    `json:"custom_field_value2,omitempty"` // 创建时字段custom_field2定义类型的最新数值，限制为4个汉字，12字节
+	CustomFieldValue3 string                    `json:"custom_field_value3,omitempty"` // 创建时字段custom_field3定义类型的最新数值，限制为4个汉字，12字节
+	NotifyOptional    *MemberCardNotifyOptional `json:"notify_optional,omitempty"`     // 控制原生消息结构体，包含各字段的消息控制字段
+}
+
+// NewMemberCardUpdateUser 更新code对应的会员信息
+func NewMemberCardUpdateUser(cardID, code string) *MemberCardUpdateUser {
+	return &MemberCardUpdateUser{CardID: cardID, Code: code}
+}
+
+// SetBonus 设置积分全量值
+func (u *MemberCardUpdateUser) SetBonus(bonus int, record string) *MemberCardUpdateUser {
+	u.Bonus, u.AddBonus, u.RecordBonus = &bonus, 0, record
+	return u
+}
+
+// ChangeBonus 增加或减少积分
+func (u *MemberCardUpdateUser) ChangeBonus(delta int, record string) *MemberCardUpdateUser {
+	u.Bonus, u.AddBonus, u.RecordBonus = nil, delta, record
+	return u
+}
+
+// SetBalance 设置余额全量值，单位为分
+func (u *MemberCardUpdateUser) SetBalance(balance int, record string) *MemberCardUpdateUser {
+	u.Balance, u.AddBalance, u.RecordBalance = &balance, 0, record
+	return u
+}
+
+// ChangeBalance 增加或减少余额，单位为分
+func (u *MemberCardUpdateUser) ChangeBalance(delta int, record string) *MemberCardUpdateUser {
+	u.Balance, u.AddBalance, u.RecordBalance = nil, delta, record
+	return u
+}
+
+// SetCustomField 设置自定义会员信息类目的值，index为1-3
+func (u *MemberCardUpdateUser) SetCustomField(index int, value string) *MemberCardUpdateUser {
+	switch index {
+	case 1:
+		u.CustomFieldValue1 = value
+	case 2:
+		u.CustomFieldValue2 = value
+	case 3:
+		u.CustomFieldValue3 = value
+	}
+	return u
+}
+
+// MemberCardUpdateResult 更新会员信息的结果
+type MemberCardUpdateResult struct {
+	ResultBonus   int    `json:"result_bonus"`   // 当前用户积分总额
+	ResultBalance int    `json:"result_balance"` // 当前用户预存总金额
+	OpenID        string `json:"openid"`         // 用户openid
+}
+
+// MemberCardSubmitUserInfoEvent 用户通过一键激活的方式提交信息并点击激活或者用户修改会员卡信息后的事件推送
+type MemberCardSubmitUserInfoEvent struct {
+	EventMessage
+	CardID       string `xml:"CardId"`       // 卡券ID
+	UserCardCode string `xml:"UserCardCode"` // 卡券Code码
+}
+
+// MemberCardUpdateEvent 会员卡积分余额发生变动时的事件推送
+type MemberCardUpdateEvent struct {
+	EventMessage
+	CardID        string `xml:"CardId"`        // 卡券ID
+	UserCardCode  string `xml:"UserCardCode"`  // 卡券Code码
+	ModifyBonus   int    `xml:"ModifyBonus"`   // 变动的积分值
+	ModifyBalance int    `xml:"ModifyBalance"` // 变动的余额值
+}
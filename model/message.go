@@ -32,6 +32,11 @@ const (
 	MsgTypeTransfer        MsgType = "transfer_customer_service" //表示消息消息转发到客服
 	MsgTypeEvent           MsgType = "event"                     //表示事件推送消息
 	MsgTypeMiniprogrampage MsgType = "miniprogrampage"
+	MsgTypeMpnews          MsgType = "mpnews"        //表示图文消息（点击跳转到图文消息页面）[限客服/群发]
+	MsgTypeMpnewsArticle   MsgType = "mpnewsarticle" //表示已发布的图文消息[限客服]
+	MsgTypeMsgMenu         MsgType = "msgmenu"       //表示菜单消息[限客服]
+	MsgTypeWxCard          MsgType = "wxcard"        //表示卡券消息[限客服/群发]
+	MsgTypeMpvideo         MsgType = "mpvideo"       //表示视频消息[限群发]
 )
 
 /*MSGCDATA MSGCDATA */
@@ -108,6 +113,19 @@ const (
 	EventTypeVerifyExpired              EventType = "verify_expired"               // 认证过期失效通知审通知
 	EventTypePoiCheckNotify             EventType = "poi_check_notify"             // 审核事件推送
 	EventTypeMerchantOrder              EventType = "merchant_order"               //订单付款通知
+	EventTypeSubscribeMsgPopup          EventType = "subscribe_msg_popup_event"    // 用户操作订阅通知弹窗
+	EventTypeSubscribeMsgChange         EventType = "subscribe_msg_change_event"   // 用户管理订阅通知
+	EventTypeSubscribeMsgSent           EventType = "subscribe_msg_sent_event"     // 发送订阅通知
+	EventTypePublishJobFinish           EventType = "PUBLISHJOBFINISH"             // 发布任务完成
+	EventTypeMassSendJobFinish          EventType = "MASSSENDJOBFINISH"            // 群发结果
+	EventTypeSubmitMemberCardUserInfo   EventType = "submit_membercard_user_info"  // 用户通过一键激活的方式提交信息并点击激活或者用户修改会员卡信息
+	EventTypeUpdateMemberCard           EventType = "update_member_card"           // 会员卡内容发生变动（积分、余额）
+	EventTypeCardPassCheck              EventType = "card_pass_check"              // 卡券审核通过
+	EventTypeCardNotPassCheck           EventType = "card_not_pass_check"          // 卡券审核未通过
+	EventTypeUserGetCard                EventType = "user_get_card"                // 用户领取卡券
+	EventTypeUserGiftingCard            EventType = "user_gifting_card"            // 用户转赠卡券
+	EventTypeUserDelCard                EventType = "user_del_card"                // 用户删除卡券
+	EventTypeUserConsumeCard            EventType = "user_consume_card"            // 卡券被核销
 )
 
 /*EVTCDATA EVTCDATA */
@@ -120,6 +138,16 @@ type Event struct {
 	Event EVTCDATA
 }
 
+/*EventMessage 事件推送的公共字段，具体事件结构体内嵌此类型 */
+type EventMessage struct {
+	XMLName      xml.Name  `xml:"xml"`
+	ToUserName   string    `xml:"ToUserName"`   // 开发者微信号
+	FromUserName string    `xml:"FromUserName"` // 发送方帐号（一个OpenID）
+	CreateTime   int64     `xml:"CreateTime"`   // 消息创建时间 （整型）
+	MsgType      MsgType   `xml:"MsgType"`      // 消息类型，event
+	Event        EventType `xml:"Event"`        // 事件类型
+}
+
 /*String String */
 func (e EventType) String() string {
 	return string(e)
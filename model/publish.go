@@ -0,0 +1,42 @@
+package model
+
+// PublishStatus 发布状态
+type PublishStatus int
+
+// PublishStatusSuccess ...
+const (
+	PublishStatusSuccess      PublishStatus = 0 // 成功
+	PublishStatusPublishing   PublishStatus = 1 // 发布中
+	PublishStatusOriginalFail PublishStatus = 2 // 原创失败
+	PublishStatusFail         PublishStatus = 3 // 常规失败
+	PublishStatusAuditFail    PublishStatus = 4 // 平台审核不通过
+	PublishStatusDeleted      PublishStatus = 5 // 成功后用户删除所有文章
+	PublishStatusBanned       PublishStatus = 6 // 成功后系统封禁所有文章
+)
+
+// PublishArticleItem ...
+type PublishArticleItem struct {
+	Idx        int    `json:"idx" xml:"idx"`                 // 当发布状态为0时（即成功）时，返回文章对应的编号
+	ArticleURL string `json:"article_url" xml:"article_url"` // 当发布状态为0时（即成功）时，返回图文的永久链接
+}
+
+// PublishArticleDetail ...
+type PublishArticleDetail struct {
+	Count int                   `json:"count" xml:"count"` // 当发布状态为0时（即成功）时，返回文章数量
+	Item  []*PublishArticleItem `json:"item" xml:"item"`
+}
+
+// PublishEventInfo 发布结果
+type PublishEventInfo struct {
+	PublishID     string                `json:"publish_id" xml:"publish_id"`                             // 发布任务id
+	PublishStatus PublishStatus         `json:"publish_status" xml:"publish_status"`                     // 发布状态
+	ArticleID     string                `json:"article_id,omitempty" xml:"article_id,omitempty"`         // 当发布状态为0时（即成功）时，返回图文的 article_id，可用于“客服消息”场景
+	ArticleDetail *PublishArticleDetail `json:"article_detail,omitempty" xml:"article_detail,omitempty"` // 当发布状态为0时（即成功）时，返回文章详细信息
+	FailIdx       []int                 `json:"fail_idx,omitempty" xml:"fail_idx,omitempty"`             // 当发布状态为2或4时，返回不通过的文章编号，第一篇为 1；其他发布状态则为空
+}
+
+// PublishJobFinishEvent 发布任务完成事件推送
+type PublishJobFinishEvent struct {
+	EventMessage
+	PublishEventInfo PublishEventInfo `xml:"PublishEventInfo"`
+}
@@ -1,5 +1,10 @@
 package model
 
+import (
+	"strings"
+	"time"
+)
+
 /*QrCodeScene QrCodeScene*/
 type QrCodeScene struct {
 	SceneID  int    `json:"scene_id,omitempty"`
@@ -48,6 +53,39 @@ type QrCodeActionName string
 const (
 	QrMultipleCard  QrCodeActionName = "QR_MULTIPLE_CARD"   //QrMultipleCard QrMultipleCard
 	QrCard          QrCodeActionName = "QR_CARD"            //QrCard QrCard
-	QrScene         QrCodeActionName = "QR_SCENE"           //QrScene QrScene
-	QrLimitStrScene QrCodeActionName = "QR_LIMIT_STR_SCENE" //QrLimitStrScene QrLimitStrScene
+	QrScene         QrCodeActionName = "QR_SCENE"           //QrScene 临时的整型参数值
+	QrStrScene      QrCodeActionName = "QR_STR_SCENE"       //QrStrScene 临时的字符串参数值
+	QrLimitScene    QrCodeActionName = "QR_LIMIT_SCENE"     //QrLimitScene 永久的整型参数值
+	QrLimitStrScene QrCodeActionName = "QR_LIMIT_STR_SCENE" //QrLimitStrScene 永久的字符串参数值
 )
+
+// QrCodeScenePrefix 未关注用户扫描带参数二维码关注后，subscribe事件的EventKey前缀
+const QrCodeScenePrefix = "qrscene_"
+
+// QrCodeTicket 创建二维码返回的ticket
+type QrCodeTicket struct {
+	Ticket        string    `json:"ticket"`                   // 获取的二维码ticket，凭借此ticket可以在有效时间内换取二维码
+	ExpireSeconds int       `json:"expire_seconds,omitempty"` // 该二维码有效时间，以秒为单位，永久二维码为0
+	URL           string    `json:"url"`                      // 二维码图片解析后的地址，开发者可根据该地址自行生成需要的二维码图片
+	ExpiresAt     time.Time `json:"-"`                        // 根据expire_seconds计算的过期时间，永久二维码为零值
+}
+
+// QrCodeScanEvent 扫描带参数二维码事件
+// 用户未关注时，进行关注后的事件推送Event为subscribe，EventKey为qrscene_为前缀的二维码参数值；
+// 用户已关注时的事件推送Event为SCAN，EventKey为创建二维码时的二维码参数值
+type QrCodeScanEvent struct {
+	EventMessage
+	EventKey string `xml:"EventKey"` // 事件KEY值
+	Ticket   string `xml:"Ticket"`   // 二维码的ticket，可用来换取二维码图片
+}
+
+// Scene 返回二维码参数值，不是扫描带参数二维码产生的事件时返回false
+func (e *QrCodeScanEvent) Scene() (string, bool) {
+	switch {
+	case e.Event.Compare(EventTypeScan) == 0:
+		return e.EventKey, e.EventKey != ""
+	case e.Event.Compare(EventTypeSubscribe) == 0 && strings.HasPrefix(e.EventKey, QrCodeScenePrefix):
+		return strings.TrimPrefix(e.EventKey, QrCodeScenePrefix), true
+	}
+	return "", false
+}
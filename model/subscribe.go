@@ -0,0 +1,125 @@
+package model
+
+// SubscribeCategory 公众号类目
+type SubscribeCategory struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// SubscribeCategoryList ...
+type SubscribeCategoryList struct {
+	Data []*SubscribeCategory `json:"data"`
+}
+
+// SubscribePubTemplateTitle 类目下的公共模板
+type SubscribePubTemplateTitle struct {
+	Tid        int    `json:"tid"`        // 模版标题 id
+	Title      string `json:"title"`      // 模版标题
+	Type       int    `json:"type"`       // 模版类型，2 为一次性订阅，3 为长期订阅
+	CategoryID string `json:"categoryId"` // 模版所属类目 id
+}
+
+// SubscribePubTemplateTitleList ...
+type SubscribePubTemplateTitleList struct {
+	Count int                          `json:"count"` // 模版标题列表总数
+	Data  []*SubscribePubTemplateTitle `json:"data"`
+}
+
+// SubscribeKeyword 模板标题下的关键词
+type SubscribeKeyword struct {
+	Kid     int    `json:"kid"`     // 关键词 id，选用模板时需要
+	Name    string `json:"name"`    // 关键词内容
+	Example string `json:"example"` // 关键词内容对应的示例
+	Rule    string `json:"rule"`    // 参数类型
+}
+
+// SubscribeKeywordList ...
+type SubscribeKeywordList struct {
+	Count int                 `json:"count"`
+	Data  []*SubscribeKeyword `json:"data"`
+}
+
+// SubscribeTemplate 私有模板
+type SubscribeTemplate struct {
+	PriTmplID string `json:"priTmplId"` // 添加至帐号下的模板 id，发送订阅通知时所需
+	Title     string `json:"title"`     // 模版标题
+	Content   string `json:"content"`   // 模版内容
+	Example   string `json:"example"`   // 模板内容示例
+	Type      int    `json:"type"`      // 模版类型，2 为一次性订阅，3 为长期订阅
+}
+
+// SubscribeTemplateList ...
+type SubscribeTemplateList struct {
+	Data []*SubscribeTemplate `json:"data"`
+}
+
+// SubscribeValue ...
+type SubscribeValue struct {
+	Value string `json:"value"`
+}
+
+// SubscribeData 模板内容，格式形如 { "key1": { "value": any }, "key2": { "value": any } }
+type SubscribeData map[string]*SubscribeValue
+
+// Set ...
+func (d SubscribeData) Set(key, value string) SubscribeData {
+	d[key] = &SubscribeValue{Value: value}
+	return d
+}
+
+// SubscribeMessage 订阅通知
+type SubscribeMessage struct {
+	ToUser      string               `json:"touser"`                // 接收者（用户）的 openid
+	TemplateID  string               `json:"template_id"`           // 所需下发的订阅模板id
+	Page        string               `json:"page,omitempty"`        // 跳转网页时填写
+	MiniProgram *TemplateMiniProgram `json:"miniprogram,omitempty"` // 跳转小程序时填写
+	Data        SubscribeData        `json:"data"`                  // 模板内容
+}
+
+// NewSubscribeMessage ...
+func NewSubscribeMessage(toUser, templateID string) *SubscribeMessage {
+	return &SubscribeMessage{
+		ToUser:     toUser,
+		TemplateID: templateID,
+		Data:       SubscribeData{},
+	}
+}
+
+// SubscribePopupItem ...
+type SubscribePopupItem struct {
+	TemplateID            string `xml:"TemplateId"`            // 模板 id（一次订阅可能有多个id）
+	SubscribeStatusString string `xml:"SubscribeStatusString"` // 用户点击行为（同意、取消发送通知）accept/reject
+	PopupScene            int    `xml:"PopupScene"`            // 场景 1 弹窗来自 H5 页面, 2 弹窗来自图文消息
+}
+
+// SubscribeMsgPopupEvent 当用户触发订阅通知弹框后推送
+type SubscribeMsgPopupEvent struct {
+	EventMessage
+	List []*SubscribePopupItem `xml:"SubscribeMsgPopupEvent>List"`
+}
+
+// SubscribeChangeItem ...
+type SubscribeChangeItem struct {
+	TemplateID            string `xml:"TemplateId"`            // 模板 id（一次订阅可能有多个id）
+	SubscribeStatusString string `xml:"SubscribeStatusString"` // 用户点击行为，仅推送用户拒收通知 reject
+}
+
+// SubscribeMsgChangeEvent 当用户在服务通知管理订阅通知后推送
+type SubscribeMsgChangeEvent struct {
+	EventMessage
+	List []*SubscribeChangeItem `xml:"SubscribeMsgChangeEvent>List"`
+}
+
+// SubscribeSentItem ...
+type SubscribeSentItem struct {
+	TemplateID  string `xml:"TemplateId"`  // 模板 id（一次订阅可能有多个id）
+	MsgID       string `xml:"MsgID"`       // 消息 id
+	ErrorCode   int    `xml:"ErrorCode"`   // 推送结果状态码（0表示成功）
+	ErrorStatus string `xml:"ErrorStatus"` // 推送结果状态码文字含义
+}
+
+// SubscribeMsgSentEvent 调用订阅通知接口发送通知后推送
+type SubscribeMsgSentEvent struct {
+	EventMessage
+	List []*SubscribeSentItem `xml:"SubscribeMsgSentEvent>List"`
+}
@@ -0,0 +1,18 @@
+package model
+
+// Tag 用户标签
+type Tag struct {
+	ID    int    `json:"id"`
+	Name  string `json:"name"`
+	Count int    `json:"count,omitempty"` // 此标签下粉丝数
+}
+
+// TagList ...
+type TagList struct {
+	Tags []*Tag `json:"tags"`
+}
+
+// TagIDList ...
+type TagIDList struct {
+	TagIDList []int `json:"tagid_list"`
+}
@@ -32,3 +32,16 @@ type UserID struct {
 type UserInfoList struct {
 	UserInfoList []*UserInfo `json:"user_info_list"`
 }
+
+// UserOpenIDList ...
+type UserOpenIDList struct {
+	OpenID []string `json:"openid"`
+}
+
+// UserList 关注者列表
+type UserList struct {
+	Total      int            `json:"total"`       // 关注该公众账号的总用户数
+	Count      int            `json:"count"`       // 拉取的OPENID个数，最大值为10000
+	Data       UserOpenIDList `json:"data"`        // 列表数据，OPENID的列表
+	NextOpenID string         `json:"next_openid"` // 拉取列表的最后一个用户的OPENID
+}
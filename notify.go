@@ -25,6 +25,9 @@ type NotifyResult struct {
 	Sign       string `json:"sign,omitempty" xml:"sign,omitempty"`
 }
 
+// ErrNotifySignature 消息推送的签名校验失败
+var ErrNotifySignature = errors.New("wrong notify signature")
+
 // Notifier ...
 type Notifier interface {
 	ServeHTTP(w http.ResponseWriter, req *http.Request)
@@ -125,45 +128,55 @@ type messageNotify struct {
 	//bizMsg *cipher.BizMsg
 }
 
-// DecodeReqInfo ...
-func (n *messageNotify) decodeInfo(query url.Values, requester Requester) (util.Map, error) {
-	var bodies []byte
-	var e error
-	encryptType := query.Get("encrypt_type")
-	timeStamp := query.Get("timestamp")
-	nonce := query.Get("nonce")
-	msgSignature := query.Get("msg_signature")
-	if encryptType != "aes" {
-		p := util.Map{}
-		e = xml.Unmarshal(bodies, &p)
-		if e != nil {
-			log.Println(e)
-			return nil, e
-		}
-
-		bodies, e = n.cipher.Decrypt(&cipher.BizMsgData{
-			RSAEncrypt:   p.GetString("RSAEncrypt"),
-			TimeStamp:    timeStamp,
-			Nonce:        nonce,
-			MsgSignature: msgSignature,
-		})
+// verifySignature 校验明文模式下的signature，加密模式的msg_signature在decodeInfo中校验
+func (n *messageNotify) verifySignature(query url.Values) bool {
+	return util.GenSHA1(n.Token, query.Get("timestamp"), query.Get("nonce")) == query.Get("signature")
+}
 
-		//错误返回,并记录log
-		if e != nil {
-			log.Println(e)
-			return nil, e
+// DecodeReqInfo ...
+func (n *messageNotify) decodeInfo(query url.Values, requester Requester) ([]byte, error) {
+	bodies := requester.Bytes()
+	if query.Get("encrypt_type") != "aes" {
+		if !n.verifySignature(query) {
+			return nil, ErrNotifySignature
 		}
+		return bodies, nil
+	}
+	if n.cipher == nil {
+		return nil, errors.New("null message cipher")
 	}
+
 	p := util.Map{}
-	e = xml.Unmarshal(bodies, &p)
+	e := xml.Unmarshal(bodies, &p)
 	if e != nil {
 		log.Println(e)
 		return nil, e
 	}
-	return p, e
+	data := &cipher.BizMsgData{
+		RSAEncrypt:   p.GetString("Encrypt"),
+		TimeStamp:    query.Get("timestamp"),
+		Nonce:        query.Get("nonce"),
+		MsgSignature: query.Get("msg_signature"),
+	}
+	if util.GenSHA1(n.Token, data.TimeStamp, data.Nonce, data.RSAEncrypt) != data.MsgSignature {
+		return nil, ErrNotifySignature
+	}
+	text, e := xml.Marshal(data)
+	if e != nil {
+		return nil, e
+	}
+	data.Text = string(text)
+
+	bodies, e = n.cipher.Decrypt(data)
+	//错误返回,并记录log
+	if e != nil {
+		log.Println(e)
+		return nil, e
+	}
+	return bodies, nil
 }
 
-// DecodeReqInfo ...
+// encodeInfo 加密回复消息，返回微信要求的Encrypt/MsgSignature/TimeStamp/Nonce格式
 func (n *messageNotify) encodeInfo(p util.Map, ts, nonce string) ([]byte, error) {
 	var e error
 	bodies, e := n.cipher.Encrypt(&cipher.BizMsgData{
@@ -176,13 +189,42 @@ func (n *messageNotify) encodeInfo(p util.Map, ts, nonce string) ([]byte, error)
 		log.Println(e)
 		return nil, e
 	}
-	return bodies, nil
+	var data cipher.BizMsgData
+	if e = xml.Unmarshal(bodies, &data); e != nil {
+		return nil, e
+	}
+	return util.Map{
+		"Encrypt":      data.RSAEncrypt,
+		"MsgSignature": data.MsgSignature,
+		"TimeStamp":    data.TimeStamp,
+		"Nonce":        data.Nonce,
+	}.ToXML(), nil
 }
 
 // ServeHTTP ...
 func (n *messageNotify) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	var e error
 
+	query, e := url.ParseQuery(req.URL.RawQuery)
+	if e != nil {
+		log.Println(e)
+		return
+	}
+
+	//服务器地址验证
+	if echo := query.Get("echostr"); echo != "" {
+		if !n.verifySignature(query) {
+			log.Println(ErrNotifySignature)
+			http.Error(w, ErrNotifySignature.Error(), http.StatusForbidden)
+			return
+		}
+		_, e = w.Write([]byte(echo))
+		if e != nil {
+			log.Println(e)
+		}
+		return
+	}
+
 	if n.RequestHook == nil {
 		log.Println(errors.New("null notify callback "))
 		return
@@ -193,24 +235,33 @@ func (n *messageNotify) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 		return
 	}
 
-	query, e := url.ParseQuery(req.URL.RawQuery)
-	if e != nil {
-		log.Println(e)
-		return
-	}
-	maps, e := n.decodeInfo(query, requester)
+	bodies, e := n.decodeInfo(query, requester)
 	if e != nil {
 		log.Println(e)
+		if errors.Is(e, ErrNotifySignature) {
+			http.Error(w, e.Error(), http.StatusForbidden)
+		}
 		return
 	}
 
-	r, e := n.RequestHook(RebuildRequester(requester, maps))
+	r, e := n.RequestHook(XMLRequest(bodies))
 	if e != nil {
 		log.Println(e)
 		return
 	}
 
-	_, e = w.Write(r.ToXML())
+	//无需回复时返回success
+	if len(r) == 0 {
+		_, e = w.Write([]byte("success"))
+	} else {
+		reply := r.ToXML()
+		if query.Get("encrypt_type") == "aes" {
+			if reply, e = n.encodeInfo(r, query.Get("timestamp"), query.Get("nonce")); e != nil {
+				return
+			}
+		}
+		_, e = w.Write(reply)
+	}
 
 	if e != nil {
 		log.Println(e)
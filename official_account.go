@@ -9,6 +9,7 @@ import (
 	"strings"
 	"time"
 	"webox/api"
+	"webox/cipher"
 	"webox/model"
 	"webox/util"
 )
@@ -23,6 +24,7 @@ type OfficialAccount struct {
 	AccessToken *AccessToken
 	remoteURL   string
 	localHost   string
+	cacheScope
 }
 
 // NewOfficialAccount ...
@@ -32,6 +34,16 @@ func NewOfficialAccount(config *OfficialAccountProperty, options ...OfficialAcco
 		BodyType:                BodyTypeJSON,
 	}
 	officialAccount.parse(options...)
+	//OfficialAccountCache设置的缓存作为AccessToken及JSSDK的默认缓存
+	if officialAccount.AccessToken != nil {
+		officialAccount.AccessToken.inherit(&officialAccount.cacheScope)
+	}
+	if officialAccount.jssdk != nil {
+		officialAccount.jssdk.inherit(&officialAccount.cacheScope)
+		if officialAccount.jssdk.AccessToken != nil {
+			officialAccount.jssdk.AccessToken.inherit(&officialAccount.jssdk.cacheScope)
+		}
+	}
 	officialAccount.client = officialAccount.Client()
 	return officialAccount
 }
@@ -76,6 +88,26 @@ func (obj *OfficialAccount) HandleAuthorize(hooks ...any) Notifier {
 	return notify
 }
 
+// HandleMessageNotify ...
+func (obj *OfficialAccount) HandleMessageNotify(hook RequestHook) ServeHTTPFunc {
+	return obj.HandleMessage(hook).ServeHTTP
+}
+
+// HandleMessage 接收普通消息及事件推送，hook中可将Requester解析为model中对应的消息/事件结构体
+func (obj *OfficialAccount) HandleMessage(hook RequestHook) Notifier {
+	notify := &messageNotify{
+		OfficialAccount: obj,
+		RequestHook:     hook,
+	}
+	if obj.AesKey != "" {
+		notify.cipher = cipher.New(cipher.BizMsg,
+			cipher.OptionKey(obj.AesKey),
+			cipher.OptionToken(obj.Token),
+			cipher.OptionID(obj.AppID))
+	}
+	return notify
+}
+
 // GetUserInfo ...
 func (obj *OfficialAccount) GetUserInfo(token *Token) (user *WechatUser, e error) {
 	p := util.Map{
@@ -167,7 +199,10 @@ func (obj *OfficialAccount) RedirectURI() string {
 
 // RemoteURL ...
 func (obj *OfficialAccount) RemoteURL() string {
-	return obj.remoteURL
+	if obj != nil && obj.remoteURL != "" {
+		return obj.remoteURL
+	}
+	return api.ApiWeixin
 }
 
 /*
@@ -247,10 +282,11 @@ func (obj *OfficialAccount) MessageStatus(msgID string) Responder {
 
 }
 
-// MessageSendText ...
-func (obj *OfficialAccount) MessageSendText() {
+// MessageSendText 发送文本客服消息
+// see MessageCustomSend
+func (obj *OfficialAccount) MessageSendText(toUser, content string) Responder {
 
-	//TODO:
+	return obj.MessageCustomSend(model.NewCustomText(toUser, content))
 }
 
 // CreateCardLandingPage 创建货架接口
@@ -367,7 +403,7 @@ func (obj *OfficialAccount) CreateCardQrCode(action *model.QrCodeAction) Respond
 //
 //	HTTP请求方式: POST
 //	URL: https://api.weixin.qq.com/card/create?access_token=ACCESS_TOKEN
-//	type *OneCard or Map
+//	type *model.Card or Map
 func (obj *OfficialAccount) CardCreate(maps util.MapAble) Responder {
 
 	u := util.URL(obj.RemoteURL(), api.CreateCard)
@@ -439,9 +475,11 @@ func (obj *OfficialAccount) CardCategories() Responder {
 func (obj *OfficialAccount) GetCardBatch(offset, count int, statusList []model.CardStatus) Responder {
 
 	p := util.Map{
-		"offset":      offset,
-		"count":       count,
-		"status_list": statusList,
+		"offset": offset,
+		"count":  count,
+	}
+	if len(statusList) > 0 {
+		p.Set("status_list", statusList)
 	}
 	u := util.URL(obj.RemoteURL(), api.GetCardBatch)
 	return obj.Client().Post(context.Background(), u, nil, p)
@@ -488,16 +526,20 @@ func (obj *OfficialAccount) SetCardPayCell(cardID string, isOpen bool) Responder
 func (obj *OfficialAccount) ModifyCardStock(cardID string, option util.Map) Responder {
 
 	u := util.URL(obj.RemoteURL(), api.ModifyCardStock)
-	return obj.Client().Post(context.Background(), u, nil, util.Map{"card_id": cardID})
+	return obj.Client().Post(context.Background(), u, nil, util.CombineMaps(util.Map{"card_id": cardID}, option))
 }
 
-// GetCardAPITicket get ticket
+// GetCardAPITicket 获取卡券api_ticket(type=wx_card)，用于wx.addCard及wx.chooseCard的签名
 func (obj *OfficialAccount) GetCardAPITicket(refresh bool) (string, error) {
 	jssdk, err := obj.JSSDK()
 	if err != nil {
 		return "", err
 	}
-	return jssdk.GetTicket("wx_card", refresh), nil
+	ticket := jssdk.GetTicket("wx_card", refresh)
+	if ticket == "" {
+		return "", errors.New("get wx_card api_ticket failed")
+	}
+	return ticket, nil
 }
 
 // JSSDK ...
@@ -879,6 +921,7 @@ const (
 	MediaTypeVoice MediaType = "voice"
 	MediaTypeVideo MediaType = "video"
 	MediaTypeThumb MediaType = "thumb"
+	MediaTypeNews  MediaType = "news" // 图文，仅用于永久素材列表
 )
 
 /*String transfer MediaType to string */
@@ -905,9 +948,7 @@ func (obj *OfficialAccount) MaterialAddMaterial(filePath string, mediaType Media
 		log.Println("please use MaterialUploadVideo() function")
 	}
 	u := util.URL(obj.RemoteURL(), api.AddMaterial)
-	p := obj.AccessToken.KeyMap()
-	p.Set("type", mediaType)
-	return Upload(u, p, util.Map{"media": filePath})
+	return obj.Client().Upload(context.Background(), u, util.Map{"type": mediaType.String()}, util.Map{"media": filePath})
 }
 
 // MaterialUploadVideo 新增其他类型永久素材
@@ -916,9 +957,7 @@ func (obj *OfficialAccount) MaterialAddMaterial(filePath string, mediaType Media
 func (obj *OfficialAccount) MaterialUploadVideo(filePath string, title, introduction string) Responder {
 
 	u := util.URL(obj.RemoteURL(), api.AddMaterial)
-	p := obj.AccessToken.KeyMap()
-	p.Set("type", MediaTypeVideo)
-	return Upload(u, p, util.Map{
+	return obj.Client().Upload(context.Background(), u, util.Map{"type": MediaTypeVideo.String()}, util.Map{
 		"media": filePath,
 		"description": util.Map{
 			"title":        title,
@@ -1010,9 +1049,7 @@ media	是	form-data中媒体文件标识，有filename、filelength、content-ty
 func (obj *OfficialAccount) MediaUpload(filePath string, mediaType MediaType) Responder {
 
 	u := util.URL(obj.RemoteURL(), api.UploadMedia)
-	p := obj.AccessToken.KeyMap()
-	p.Set("type", mediaType)
-	return Upload(u, p, util.Map{"media": filePath})
+	return obj.Client().Upload(context.Background(), u, util.Map{"type": mediaType.String()}, util.Map{"media": filePath})
 }
 
 /*
@@ -1071,8 +1108,7 @@ func (obj *OfficialAccount) MediaGetJSSDK(mediaID string) Responder {
 func (obj *OfficialAccount) UploadImg(name string, filePath string) Responder {
 
 	u := util.URL(obj.RemoteURL(), api.UploadImg)
-	p := obj.AccessToken.KeyMap()
-	return Upload(u, p, util.Map{name: filePath})
+	return obj.Client().Upload(context.Background(), u, nil, util.Map{name: filePath})
 }
 
 // MediaUploadImg 上传图文消息内的图片获取URL
@@ -1180,12 +1216,11 @@ https://api.weixin.qq.com/cgi-bin/menu/delete?access_token=ACCESS_TOKEN
 */
 func (obj *OfficialAccount) MenuDelete(menuID int) Responder {
 
-	u := util.URL(obj.RemoteURL(), api.DeleteMenuConditional)
 	if menuID == 0 {
-		u = util.URL(obj.RemoteURL(), api.DeleteMenu)
+		u := util.URL(obj.RemoteURL(), api.DeleteMenu)
 		return obj.Client().Get(context.Background(), u, nil)
 	}
-	return obj.Client().Post(context.Background(), u, nil, util.Map{"menuid": menuID})
+	return obj.MenuDeleteConditional(int64(menuID))
 }
 
 /*
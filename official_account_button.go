@@ -1,8 +1,13 @@
 package webox
 
 import (
-	"webox/model"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
 	"webox/util"
+
+	jsoniter "github.com/json-iterator/go"
 )
 
 /*Button Button */
@@ -10,26 +15,155 @@ type Button struct {
 	util.Map
 }
 
-/*MatchRule MatchRule*/
+/*MatchRule 个性化菜单匹配规则，至少要有一个匹配信息是不为空的 */
 type MatchRule struct {
-	TagID              string `json:"tag_id,omitempty"`
-	Sex                string `json:"sex,omitempty"`
-	Country            string `json:"country,omitempty"`
-	Province           string `json:"province,omitempty"`
-	City               string `json:"city,omitempty"`
-	ClientPlatformType string `json:"client_platform_type,omitempty"`
-	Language           string `json:"language,omitempty"`
+	TagID              string `json:"tag_id,omitempty" yaml:"tag_id,omitempty"`                             // 用户标签的id
+	Sex                string `json:"sex,omitempty" yaml:"sex,omitempty"`                                   // 性别：男（1）女（2）
+	Country            string `json:"country,omitempty" yaml:"country,omitempty"`                           // 国家信息
+	Province           string `json:"province,omitempty" yaml:"province,omitempty"`                         // 省份信息，不填则不做匹配
+	City               string `json:"city,omitempty" yaml:"city,omitempty"`                                 // 城市信息，不填则不做匹配
+	ClientPlatformType string `json:"client_platform_type,omitempty" yaml:"client_platform_type,omitempty"` // 客户端版本：IOS(1), Android(2),Others(3)
+	Language           string `json:"language,omitempty" yaml:"language,omitempty"`                         // 语言信息
+}
+
+// UnmarshalJSON 查询菜单接口返回的sex、client_platform_type等字段为数值，标签字段为group_id，
+// 数值统一转换为字符串，group_id作为tag_id
+func (m *MatchRule) UnmarshalJSON(data []byte) error {
+	var raw map[string]jsoniter.RawMessage
+	if e := jsoniter.Unmarshal(data, &raw); e != nil {
+		return e
+	}
+	*m = MatchRule{}
+	fields := map[string]*string{
+		"group_id":             &m.TagID,
+		"tag_id":               &m.TagID,
+		"sex":                  &m.Sex,
+		"country":              &m.Country,
+		"province":             &m.Province,
+		"city":                 &m.City,
+		"client_platform_type": &m.ClientPlatformType,
+		"language":             &m.Language,
+	}
+	//tag_id在group_id之后处理，两者同时存在时以tag_id为准
+	for _, key := range []string{"group_id", "tag_id", "sex", "country", "province", "city", "client_platform_type", "language"} {
+		v, ok := raw[key]
+		if !ok {
+			continue
+		}
+		s, e := matchRuleValue(v)
+		if e != nil {
+			return fmt.Errorf("matchrule.%s: %w", key, e)
+		}
+		if s != "" || key != "tag_id" {
+			*fields[key] = s
+		}
+	}
+	return nil
+}
+
+// matchRuleValue 将字符串、数值或null转换为字符串
+func matchRuleValue(v jsoniter.RawMessage) (string, error) {
+	text := strings.TrimSpace(string(v))
+	if text == "" || text == "null" {
+		return "", nil
+	}
+	if strings.HasPrefix(text, `"`) {
+		var s string
+		e := jsoniter.Unmarshal(v, &s)
+		return s, e
+	}
+	if _, e := strconv.ParseFloat(text, 64); e != nil {
+		return "", fmt.Errorf("unexpected value %s", text)
+	}
+	return text, nil
+}
+
+// normalized 返回规范化的匹配规则：去除首尾空白，数值字段去除前导0，用于比较两条规则是否相同
+func (m *MatchRule) normalized() MatchRule {
+	r := MatchRule{
+		TagID:              strings.TrimSpace(m.TagID),
+		Sex:                strings.TrimSpace(m.Sex),
+		Country:            strings.TrimSpace(m.Country),
+		Province:           strings.TrimSpace(m.Province),
+		City:               strings.TrimSpace(m.City),
+		ClientPlatformType: strings.TrimSpace(m.ClientPlatformType),
+		Language:           strings.TrimSpace(m.Language),
+	}
+	for _, v := range []*string{&r.TagID, &r.Sex, &r.ClientPlatformType} {
+		if n, e := strconv.ParseUint(*v, 10, 64); e == nil {
+			*v = strconv.FormatUint(n, 10)
+		}
+	}
+	return r
+}
+
+var matchRuleLanguages = []string{
+	"zh_CN", "zh_TW", "zh_HK", "en", "id", "ms", "es", "ko", "it", "ja", "pl",
+	"pt", "ru", "th", "vi", "ar", "hi", "he", "tr", "de", "fr",
+}
+
+/*Validate 校验匹配规则，返回第一个不合法的字段 */
+func (m *MatchRule) Validate() *MenuError {
+	if *m == (MatchRule{}) {
+		return &MenuError{Path: "matchrule", Msg: "at least one field is required"}
+	}
+	if m.TagID != "" {
+		if _, e := strconv.ParseUint(m.TagID, 10, 64); e != nil {
+			return &MenuError{Path: "matchrule.tag_id", Msg: fmt.Sprintf("invalid tag id %q", m.TagID)}
+		}
+	}
+	if m.Sex != "" && m.Sex != "1" && m.Sex != "2" {
+		return &MenuError{Path: "matchrule.sex", Msg: fmt.Sprintf("must be 1 or 2, got %q", m.Sex)}
+	}
+	if m.Province != "" && m.Country == "" {
+		return &MenuError{Path: "matchrule.country", Msg: "is required when province is set"}
+	}
+	if m.City != "" && m.Province == "" {
+		return &MenuError{Path: "matchrule.province", Msg: "is required when city is set"}
+	}
+	if p := m.ClientPlatformType; p != "" && p != "1" && p != "2" && p != "3" {
+		return &MenuError{Path: "matchrule.client_platform_type", Msg: fmt.Sprintf("must be 1, 2 or 3, got %q", p)}
+	}
+	if m.Language != "" && !slices.Contains(matchRuleLanguages, m.Language) {
+		return &MenuError{Path: "matchrule.language", Msg: fmt.Sprintf("unsupported language %q", m.Language)}
+	}
+	return nil
 }
 
 /*NewClickButton NewClickButton*/
 func NewClickButton(name, key string) *Button {
-	return newButton(model.EventTypeClick, util.Map{"name": name, "key": key})
+	return newButton(MenuButtonTypeClick, util.Map{"name": name, "key": key})
 
 }
 
 /*NewViewButton NewViewButton*/
 func NewViewButton(name, url string) *Button {
-	return newButton(model.EventTypeView, util.Map{"name": name, "url": url})
+	return newButton(MenuButtonTypeView, util.Map{"name": name, "url": url})
+}
+
+/*NewKeyButton 扫码、发图、地理位置选择等以key响应的按钮，typ如MenuButtonTypeScancodePush */
+func NewKeyButton(typ MenuButtonType, name, key string) *Button {
+	return newButton(typ, util.Map{"name": name, "key": key})
+}
+
+/*NewMediaIDButton NewMediaIDButton*/
+func NewMediaIDButton(name, mediaID string) *Button {
+	return newButton(MenuButtonTypeMediaID, util.Map{"name": name, "media_id": mediaID})
+}
+
+/*NewArticleIDButton NewArticleIDButton*/
+func NewArticleIDButton(name, articleID string) *Button {
+	return newButton(MenuButtonTypeArticleID, util.Map{"name": name, "article_id": articleID})
+}
+
+/*NewArticleViewLimitedButton NewArticleViewLimitedButton*/
+func NewArticleViewLimitedButton(name, articleID string) *Button {
+	return newButton(MenuButtonTypeArticleViewLimited, util.Map{"name": name, "article_id": articleID})
+}
+
+/*NewMiniProgramButton url为不支持小程序的老版本客户端将打开的网页*/
+func NewMiniProgramButton(name, url, appID, pagePath string) *Button {
+	return newButton(MenuButtonTypeMiniProgram, util.Map{"name": name, "url": url, "appid": appID, "pagepath": pagePath})
 }
 
 /*NewSubButton NewSubButton*/
@@ -37,7 +171,7 @@ func NewSubButton(name string, sub []*Button) *Button {
 	return newButton("", util.Map{"name": name, "key": "testkey", "sub_button": sub})
 }
 
-func newButton(typ model.EventType, val util.Map) *Button {
+func newButton(typ MenuButtonType, val util.Map) *Button {
 	button := NewBaseButton()
 	if typ != "" {
 		button.Set("type", typ)
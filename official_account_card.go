@@ -0,0 +1,343 @@
+package webox
+
+import (
+	"context"
+	"iter"
+	"strings"
+	"webox/api"
+	"webox/model"
+	"webox/util"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// CardAdd 校验并创建卡券，返回card_id
+// HTTP请求方式: POST
+// https://api.weixin.qq.com/card/create?access_token=ACCESS_TOKEN
+func (obj *OfficialAccount) CardAdd(card *model.Card) (cardID string, e error) {
+
+	if e = card.Validate(); e != nil {
+		return "", e
+	}
+	resp := obj.CardCreate(card)
+	if e = resp.Error(); e != nil {
+		return "", e
+	}
+	var result struct {
+		CardID string `json:"card_id"`
+	}
+	if e = resp.Unmarshal(&result); e != nil {
+		return "", e
+	}
+	return result.CardID, nil
+}
+
+// CardGet 查看卡券详情
+// HTTP请求方式: POST
+// https://api.weixin.qq.com/card/get?access_token=TOKEN
+func (obj *OfficialAccount) CardGet(cardID string) (card *model.Card, e error) {
+
+	resp := obj.GetCard(cardID)
+	if e = resp.Error(); e != nil {
+		return nil, e
+	}
+	var result struct {
+		Card *model.Card `json:"card"`
+	}
+	if e = resp.Unmarshal(&result); e != nil {
+		return nil, e
+	}
+	return result.Card, nil
+}
+
+// CardBatchGet 批量查询卡券列表，count不能超过50；statusList为空时查询全部状态
+// HTTP请求方式: POST
+// https://api.weixin.qq.com/card/batchget?access_token=TOKEN
+func (obj *OfficialAccount) CardBatchGet(offset, count int, statusList ...model.CardStatus) (list *model.CardIDList, e error) {
+
+	resp := obj.GetCardBatch(offset, count, statusList)
+	if e = resp.Error(); e != nil {
+		return nil, e
+	}
+	list = new(model.CardIDList)
+	if e = resp.Unmarshal(list); e != nil {
+		return nil, e
+	}
+	return list, nil
+}
+
+// CardIDs 遍历指定状态的全部card_id
+func (obj *OfficialAccount) CardIDs(statusList ...model.CardStatus) iter.Seq2[string, error] {
+	return func(yield func(string, error) bool) {
+		offset := 0
+		for {
+			list, e := obj.CardBatchGet(offset, model.CardBatchGetMaxCount, statusList...)
+			if e != nil {
+				yield("", e)
+				return
+			}
+			for _, id := range list.CardIDList {
+				if !yield(id, nil) {
+					return
+				}
+			}
+			offset += len(list.CardIDList)
+			if len(list.CardIDList) == 0 || offset >= list.TotalNum {
+				return
+			}
+		}
+	}
+}
+
+// Cards 遍历指定状态的全部卡券详情，每个卡券调用一次查看卡券详情接口
+func (obj *OfficialAccount) Cards(statusList ...model.CardStatus) iter.Seq2[*model.Card, error] {
+	return func(yield func(*model.Card, error) bool) {
+		for id, e := range obj.CardIDs(statusList...) {
+			if e != nil {
+				yield(nil, e)
+				return
+			}
+			card, e := obj.CardGet(id)
+			if !yield(card, e) || e != nil {
+				return
+			}
+		}
+	}
+}
+
+// CardUpdateInfo 更改卡券信息，fields为卡券类型对应的字段，如base_info、bonus_rule等
+// 返回sendCheck为true时表示此次更新需要重新审核，卡券审核通过前不能再次更新
+// HTTP请求方式: POST
+// https://api.weixin.qq.com/card/update?access_token=TOKEN
+func (obj *OfficialAccount) CardUpdateInfo(cardID string, cardType model.CardType, fields util.Map) (sendCheck bool, e error) {
+
+	resp := obj.UpdateCard(cardID, util.Map{strings.ToLower(cardType.String()): fields})
+	if e = resp.Error(); e != nil {
+		return false, e
+	}
+	var result struct {
+		SendCheck bool `json:"send_check"`
+	}
+	if e = resp.Unmarshal(&result); e != nil {
+		return false, e
+	}
+	return result.SendCheck, nil
+}
+
+// CardCodeGet 查询Code，自定义code卡券须填写cardID
+// checkConsume为true时，已核销或不可用的code会返回错误码
+// HTTP请求方式: POST
+// https://api.weixin.qq.com/card/code/get?access_token=TOKEN
+func (obj *OfficialAccount) CardCodeGet(code, cardID string, checkConsume bool) (info *model.CardCodeInfo, e error) {
+
+	p := util.Map{"code": code, "check_consume": checkConsume}
+	if cardID != "" {
+		p.Set("card_id", cardID)
+	}
+	resp := obj.GetCardCode(p)
+	if e = resp.Error(); e != nil {
+		return nil, e
+	}
+	info = new(model.CardCodeInfo)
+	if e = resp.Unmarshal(info); e != nil {
+		return nil, e
+	}
+	return info, nil
+}
+
+// CardCodeConsume 核销Code，自定义code卡券须填写cardID
+// HTTP请求方式: POST
+// https://api.weixin.qq.com/card/code/consume?access_token=TOKEN
+func (obj *OfficialAccount) CardCodeConsume(code, cardID string) (result *model.CardCodeConsumeResult, e error) {
+
+	p := util.Map{"code": code}
+	if cardID != "" {
+		p.Set("card_id", cardID)
+	}
+	result = new(model.CardCodeConsumeResult)
+	if e = obj.cardPost(api.ConsumeCardCode, p, result); e != nil {
+		return nil, e
+	}
+	return result, nil
+}
+
+// CardCodeDecrypt 解码卡券跳转外链或事件中加密的encrypt_code，得到真实code
+// HTTP请求方式: POST
+// https://api.weixin.qq.com/card/code/decrypt?access_token=TOKEN
+func (obj *OfficialAccount) CardCodeDecrypt(encryptCode string) (code string, e error) {
+
+	var result struct {
+		Code string `json:"code"`
+	}
+	e = obj.cardPost(api.DecryptCardCode, util.Map{"encrypt_code": encryptCode}, &result)
+	return result.Code, e
+}
+
+// CardCodeMark 标记或取消标记code已被用户占用，仅朋友的券需要调用
+// HTTP请求方式: POST
+// https://api.weixin.qq.com/card/code/mark?access_token=TOKEN
+func (obj *OfficialAccount) CardCodeMark(code, cardID, openID string, isMark bool) error {
+
+	return obj.cardPost(api.MarkCardCode, util.Map{
+		"code":    code,
+		"card_id": cardID,
+		"openid":  openID,
+		"is_mark": isMark,
+	}, nil)
+}
+
+// CardCodeUnavailable 设置卡券失效，失效后用户卡包中的卡券将无法使用且不可恢复
+// HTTP请求方式: POST
+// https://api.weixin.qq.com/card/code/unavailable?access_token=TOKEN
+func (obj *OfficialAccount) CardCodeUnavailable(code, cardID, reason string) error {
+
+	p := util.Map{"code": code}
+	if cardID != "" {
+		p.Set("card_id", cardID)
+	}
+	if reason != "" {
+		p.Set("reason", reason)
+	}
+	return obj.cardPost(api.UnavailableCardCode, p, nil)
+}
+
+// cardPost 发送请求并在result不为nil时解析返回结果
+func (obj *OfficialAccount) cardPost(uri string, body, result any) error {
+	u := util.URL(obj.RemoteURL(), uri)
+	resp := obj.Client().Post(context.Background(), u, nil, body)
+	if e := resp.Error(); e != nil {
+		return e
+	}
+	if result == nil {
+		return nil
+	}
+	return resp.Unmarshal(result)
+}
+
+// BuildCardExt 填充cardExt的时间戳及随机串并签名
+// 签名为api_ticket、timestamp、card_id、code、openid、nonce_str的值按字典序排序后拼接的SHA1
+func BuildCardExt(apiTicket, cardID string, ext *model.CardExt) *model.CardExt {
+	if ext.Timestamp == "" {
+		ext.Timestamp = util.Time()
+	}
+	if ext.NonceStr == "" {
+		ext.NonceStr = util.GenerateNonceStr()
+	}
+	ext.Signature = util.GenSHA1(apiTicket, ext.Timestamp, cardID, ext.Code, ext.OpenID, ext.NonceStr)
+	return ext
+}
+
+// CardAddItem 生成JSSDK添加卡券(wx.addCard)接口cardList的元素，ext为nil时只签名card_id
+func (obj *OfficialAccount) CardAddItem(cardID string, ext *model.CardExt) (item *model.CardAddItem, e error) {
+
+	ticket, e := obj.GetCardAPITicket(false)
+	if e != nil {
+		return nil, e
+	}
+	if ext == nil {
+		ext = new(model.CardExt)
+	}
+	s, e := jsoniter.MarshalToString(BuildCardExt(ticket, cardID, ext))
+	if e != nil {
+		return nil, e
+	}
+	return &model.CardAddItem{CardID: cardID, CardExt: s}, nil
+}
+
+// BuildCardChooseConfig 填充wx.chooseCard参数的时间戳及随机串并签名
+// 签名为api_ticket、app_id、location_id、timestamp、nonce_str、card_id、card_type的值按字典序排序后拼接的SHA1
+func BuildCardChooseConfig(apiTicket, appID string, config *model.CardChooseConfig) *model.CardChooseConfig {
+	if config.Timestamp == "" {
+		config.Timestamp = util.Time()
+	}
+	if config.NonceStr == "" {
+		config.NonceStr = util.GenerateNonceStr()
+	}
+	config.SignType = "SHA1"
+	config.CardSign = util.GenSHA1(apiTicket, appID, config.ShopID, config.Timestamp, config.NonceStr, config.CardID, config.CardType)
+	return config
+}
+
+// CardChooseConfig 生成JSSDK拉取适用卡券列表(wx.chooseCard)的参数，参数均可为空
+func (obj *OfficialAccount) CardChooseConfig(shopID string, cardType model.CardType, cardID string) (config *model.CardChooseConfig, e error) {
+
+	ticket, e := obj.GetCardAPITicket(false)
+	if e != nil {
+		return nil, e
+	}
+	config = &model.CardChooseConfig{ShopID: shopID, CardType: cardType.String(), CardID: cardID}
+	return BuildCardChooseConfig(ticket, obj.AppID, config), nil
+}
+
+// CardEventHandler 卡券事件推送的回调，未设置对应回调的消息交由HandleCardEvent的next处理
+type CardEventHandler struct {
+	OnCheck                    func(event *model.CardCheckEvent) error // card_pass_check及card_not_pass_check
+	OnUserGetCard              func(event *model.UserGetCardEvent) error
+	OnUserGiftingCard          func(event *model.UserGiftingCardEvent) error
+	OnUserDelCard              func(event *model.UserDelCardEvent) error
+	OnUserConsumeCard          func(event *model.UserConsumeCardEvent) error
+	OnSubmitMemberCardUserInfo func(event *model.MemberCardSubmitUserInfoEvent) error
+	OnUpdateMemberCard         func(event *model.MemberCardUpdateEvent) error
+}
+
+// Hook 将卡券事件分发到对应回调，卡券事件无需回复，处理后返回success
+func (h *CardEventHandler) Hook(next RequestHook) RequestHook {
+	return func(req Requester) (util.Map, error) {
+		var msg model.EventMessage
+		if e := req.Unmarshal(&msg); e != nil {
+			return nil, e
+		}
+		if msg.MsgType == model.MsgTypeEvent {
+			switch model.EventType(strings.ToLower(msg.Event.String())) {
+			case model.EventTypeCardPassCheck, model.EventTypeCardNotPassCheck:
+				if h.OnCheck != nil {
+					return nil, unmarshalCardEvent(req, h.OnCheck)
+				}
+			case model.EventTypeUserGetCard:
+				if h.OnUserGetCard != nil {
+					return nil, unmarshalCardEvent(req, h.OnUserGetCard)
+				}
+			case model.EventTypeUserGiftingCard:
+				if h.OnUserGiftingCard != nil {
+					return nil, unmarshalCardEvent(req, h.OnUserGiftingCard)
+				}
+			case model.EventTypeUserDelCard:
+				if h.OnUserDelCard != nil {
+					return nil, unmarshalCardEvent(req, h.OnUserDelCard)
+				}
+			case model.EventTypeUserConsumeCard:
+				if h.OnUserConsumeCard != nil {
+					return nil, unmarshalCardEvent(req, h.OnUserConsumeCard)
+				}
+			case model.EventTypeSubmitMemberCardUserInfo:
+				if h.OnSubmitMemberCardUserInfo != nil {
+					return nil, unmarshalCardEvent(req, h.OnSubmitMemberCardUserInfo)
+				}
+			case model.EventTypeUpdateMemberCard:
+				if h.OnUpdateMemberCard != nil {
+					return nil, unmarshalCardEvent(req, h.OnUpdateMemberCard)
+				}
+			}
+		}
+		if next == nil {
+			return nil, nil
+		}
+		return next(req)
+	}
+}
+
+func unmarshalCardEvent[T any](req Requester, fn func(event *T) error) error {
+	event := new(T)
+	if e := req.Unmarshal(event); e != nil {
+		return e
+	}
+	return fn(event)
+}
+
+// HandleCardEvent 监听消息推送，卡券事件交由handler处理，其余消息交由next处理
+func (obj *OfficialAccount) HandleCardEvent(handler *CardEventHandler, next RequestHook) Notifier {
+	if handler == nil {
+		return obj.HandleMessage(next)
+	}
+	return obj.HandleMessage(handler.Hook(next))
+}
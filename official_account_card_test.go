@@ -0,0 +1,133 @@
+package webox
+
+import (
+	"crypto/sha1"
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"webox/model"
+	"webox/util"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+func testCardBaseInfo() *model.CardBaseInfo {
+	return &model.CardBaseInfo{
+		LogoURL:     "http://mmbiz.qpic.cn/logo.png",
+		BrandName:   "海底捞",
+		CodeType:    model.CardCodeTypeQrcode,
+		Title:       "双人套餐",
+		Color:       "Color010",
+		Notice:      "请出示二维码",
+		Description: "不可与其他优惠同享",
+		DateInfo:    model.CardDataInfo{Type: model.CardDateTypeFixTerm, FixedTerm: 15},
+		Sku:         model.CardSku{Quantity: 100},
+	}
+}
+
+// TestNewCard ...
+func TestNewCard(t *testing.T) {
+	card, err := model.NewDiscountCard(testCardBaseInfo(), 30)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, _ := jsoniter.MarshalToString(card)
+	if !strings.Contains(s, `"card_type":"DISCOUNT","discount":{"base_info":{`) || !strings.Contains(s, `"discount":30}`) {
+		t.Fatalf("unexpected card json %s", s)
+	}
+
+	base := testCardBaseInfo()
+	base.Title = ""
+	base.DateInfo = model.CardDataInfo{Type: model.CardDateTypePermanent}
+	_, err = model.NewCashCard(base, 0, 0)
+	for _, want := range []string{"base_info.title is required", "reduce_cost", "DATE_TYPE_PERMANENT"} {
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("expected error containing %q, got %v", want, err)
+		}
+	}
+
+	if _, err = model.NewMemberCard(testCardBaseInfo(), &model.CardMemberCard{Prerogative: "九折"}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestCard_Unmarshal 查看卡券详情接口返回的数据
+func TestCard_Unmarshal(t *testing.T) {
+	body := `{"card_type":"GROUPON","groupon":{"base_info":{"id":"pbLatjk4T4Hx","status":"CARD_STATUS_VERIFY_OK",
+"logo_url":"http://mmbiz.qpic.cn/logo.png","code_type":"CODE_TYPE_TEXT","brand_name":"测试商户","title":"测试","color":"Color010",
+"notice":"使用时向服务员出示此券","description":"不可与其他优惠同享","date_info":{"type":"DATE_TYPE_FIX_TERM","fixed_term":90,"fixed_begin_term":0},
+"sku":{"quantity":0,"total_quantity":10000},"create_time":1457084468,"update_time":1457085014},"deal_detail":"以下锅底2选1"}}`
+
+	var card model.Card
+	if err := jsoniter.UnmarshalFromString(body, &card); err != nil {
+		t.Fatal(err)
+	}
+	info := card.Info()
+	if info == nil || info.BaseInfo.ID != "pbLatjk4T4Hx" || info.BaseInfo.Status != model.CardStatusVerifyOk || info.BaseInfo.Sku.TotalQuantity != 10000 {
+		t.Fatalf("unexpected card info %+v", info)
+	}
+	if card.Groupon.DealDetail != "以下锅底2选1" {
+		t.Fatalf("unexpected deal detail %q", card.Groupon.DealDetail)
+	}
+}
+
+// TestCardEventHandler ...
+func TestCardEventHandler(t *testing.T) {
+	body := `<xml><ToUserName><![CDATA[toUser]]></ToUserName>
+<FromUserName><![CDATA[FromUser]]></FromUserName>
+<CreateTime>123456789</CreateTime>
+<MsgType><![CDATA[event]]></MsgType>
+<Event><![CDATA[user_consume_card]]></Event>
+<CardId><![CDATA[4ZYmvkxtrLosgl7PAa3Mm4UAQNQ]]></CardId>
+<UserCardCode><![CDATA[12312312]]></UserCardCode>
+<ConsumeSource><![CDATA[FROM_API]]></ConsumeSource>
+<LocationName><![CDATA[]]></LocationName>
+<StaffOpenId><![CDATA[oFS7Fjl0WsZ9AMZqrI80nbIq8xrA]]></StaffOpenId>
+<OuterStr><![CDATA[xxxxx]]></OuterStr>
+</xml>`
+
+	var consumed *model.UserConsumeCardEvent
+	nextCalled := false
+	handler := &CardEventHandler{
+		OnUserConsumeCard: func(event *model.UserConsumeCardEvent) error {
+			consumed = event
+			return nil
+		},
+	}
+	oa := NewOfficialAccount(&OfficialAccountProperty{AppID: "wx", Token: "token"})
+	notify := oa.HandleCardEvent(handler, func(req Requester) (util.Map, error) {
+		nextCalled = true
+		return nil, nil
+	})
+
+	w := httptest.NewRecorder()
+	notify.ServeHTTP(w, httptest.NewRequest("POST", signedNotifyURL("token"), strings.NewReader(body)))
+	if w.Body.String() != "success" || nextCalled {
+		t.Fatalf("unexpected reply %q, next called %v", w.Body.String(), nextCalled)
+	}
+	if consumed == nil || consumed.UserCardCode != "12312312" || consumed.ConsumeSource != model.CardConsumeSourceAPI ||
+		consumed.StaffOpenID != "oFS7Fjl0WsZ9AMZqrI80nbIq8xrA" || consumed.OuterStr != "xxxxx" {
+		t.Fatalf("unexpected event %+v", consumed)
+	}
+
+	body = strings.Replace(body, "user_consume_card", "user_del_card", 1)
+	notify.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", signedNotifyURL("token"), strings.NewReader(body)))
+	if !nextCalled {
+		t.Fatal("unhandled card event should be passed to next")
+	}
+}
+
+// TestBuildCardExt ...
+func TestBuildCardExt(t *testing.T) {
+	ext := BuildCardExt("ticket", "card", &model.CardExt{Code: "123", Timestamp: "1404896688", NonceStr: "nonce"})
+	// 字典序: 123 1404896688 card nonce ticket，openid为空
+	want := fmt.Sprintf("%x", sha1.Sum([]byte("1231404896688cardnonceticket")))
+	if ext.Signature != want {
+		t.Fatalf("got signature %s, want %s", ext.Signature, want)
+	}
+	s, _ := jsoniter.MarshalToString(ext)
+	if s != `{"code":"123","timestamp":"1404896688","nonce_str":"nonce","signature":"`+want+`"}` {
+		t.Fatalf("unexpected card ext %s", s)
+	}
+}
@@ -0,0 +1,223 @@
+package webox
+
+import (
+	"encoding/csv"
+	"io"
+	"iter"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"webox/api"
+	"webox/model"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// DatacubeReport 数据统计接口及其单次查询允许的最大时间跨度（天）
+type DatacubeReport[T any] struct {
+	URI     string
+	MaxDays int
+}
+
+// 数据统计接口，T为接口返回list中的数据行
+var (
+	DatacubeReportUserSummary          = DatacubeReport[model.DatacubeUserSummary]{api.GetUserSummary, 7}
+	DatacubeReportUserCumulate         = DatacubeReport[model.DatacubeUserCumulate]{api.GetUserCumulate, 7}
+	DatacubeReportArticleSummary       = DatacubeReport[model.DatacubeArticleSummary]{api.GetArticleSummary, 1}
+	DatacubeReportArticleTotal         = DatacubeReport[model.DatacubeArticleTotal]{api.GetArticleTotal, 1}
+	DatacubeReportUserRead             = DatacubeReport[model.DatacubeUserRead]{api.GetUserRead, 3}
+	DatacubeReportUserReadHour         = DatacubeReport[model.DatacubeUserReadHour]{api.GetUserReadHour, 1}
+	DatacubeReportUserShare            = DatacubeReport[model.DatacubeUserShare]{api.GetUserShare, 7}
+	DatacubeReportUserShareHour        = DatacubeReport[model.DatacubeUserShareHour]{api.GetUserShareHour, 1}
+	DatacubeReportUpstreamMsg          = DatacubeReport[model.DatacubeUpstreamMsg]{api.GetUpstreamMsg, 7}
+	DatacubeReportUpstreamMsgHour      = DatacubeReport[model.DatacubeUpstreamMsgHour]{api.GetUpstreamMsgHour, 1}
+	DatacubeReportUpstreamMsgWeek      = DatacubeReport[model.DatacubeUpstreamMsg]{api.GetUpstreamMsgWeek, 30}
+	DatacubeReportUpstreamMsgMonth     = DatacubeReport[model.DatacubeUpstreamMsg]{api.GetUpstreamMsgMonth, 30}
+	DatacubeReportUpstreamMsgDist      = DatacubeReport[model.DatacubeUpstreamMsgDist]{api.GetUpstreamMsgDist, 15}
+	DatacubeReportUpstreamMsgDistWeek  = DatacubeReport[model.DatacubeUpstreamMsgDist]{api.GetUpstreamMsgDistWeek, 30}
+	DatacubeReportUpstreamMsgDistMonth = DatacubeReport[model.DatacubeUpstreamMsgDist]{api.GetUpstreamMsgDistMonth, 30}
+	DatacubeReportInterfaceSummary     = DatacubeReport[model.DatacubeInterfaceSummary]{api.GetInterfaceSummary, 30}
+	DatacubeReportInterfaceSummaryHour = DatacubeReport[model.DatacubeInterfaceSummaryHour]{api.GetInterfaceSummaryHour, 1}
+)
+
+// Datacube 数据统计客户端，将任意时间范围拆分为接口允许的窗口并发查询
+type Datacube struct {
+	officialAccount *OfficialAccount
+	concurrency     int
+}
+
+// Datacube ...
+func (obj *OfficialAccount) Datacube(options ...DatacubeOption) *Datacube {
+	datacube := &Datacube{
+		officialAccount: obj,
+		concurrency:     1,
+	}
+	for _, o := range options {
+		o(datacube)
+	}
+	if datacube.concurrency < 1 {
+		datacube.concurrency = 1
+	}
+	return datacube
+}
+
+// DatacubeWindow 查询窗口，包含起止日期
+type DatacubeWindow struct {
+	Begin time.Time
+	End   time.Time
+}
+
+// DatacubeWindows 将[begin, end]按天拆分为不超过maxDays天的窗口，maxDays小于1时按1天拆分
+func DatacubeWindows(begin, end time.Time, maxDays int) []DatacubeWindow {
+	var windows []DatacubeWindow
+	maxDays = max(maxDays, 1)
+	begin = time.Date(begin.Year(), begin.Month(), begin.Day(), 0, 0, 0, 0, begin.Location())
+	end = time.Date(end.Year(), end.Month(), end.Day(), 0, 0, 0, 0, end.Location())
+	for start := begin; !start.After(end); start = start.AddDate(0, 0, maxDays) {
+		stop := start.AddDate(0, 0, maxDays-1)
+		if stop.After(end) {
+			stop = end
+		}
+		windows = append(windows, DatacubeWindow{Begin: start, End: stop})
+	}
+	return windows
+}
+
+type datacubeResult[T any] struct {
+	rows []*T
+	err  error
+}
+
+// DatacubeRows 按日期顺序遍历[begin, end]内报表的全部数据行，窗口之间并发查询
+func DatacubeRows[T any](obj *Datacube, report DatacubeReport[T], begin, end time.Time) iter.Seq2[*T, error] {
+	return func(yield func(*T, error) bool) {
+		windows := DatacubeWindows(begin, end, report.MaxDays)
+		results := make([]chan datacubeResult[T], len(windows))
+		for i := range results {
+			results[i] = make(chan datacubeResult[T], 1)
+		}
+
+		done := make(chan struct{})
+		defer close(done)
+		go func() {
+			sem := make(chan struct{}, obj.concurrency)
+			for i, w := range windows {
+				select {
+				case sem <- struct{}{}:
+				case <-done:
+					return
+				}
+				go func() {
+					defer func() { <-sem }()
+					rows, e := datacubeFetch(obj.officialAccount, report, w)
+					results[i] <- datacubeResult[T]{rows: rows, err: e}
+				}()
+			}
+		}()
+
+		for i := range windows {
+			result := <-results[i]
+			if result.err != nil {
+				yield(nil, result.err)
+				return
+			}
+			for _, row := range result.rows {
+				if !yield(row, nil) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// DatacubeFetch 获取[begin, end]内报表的全部数据行
+func DatacubeFetch[T any](obj *Datacube, report DatacubeReport[T], begin, end time.Time) (rows []*T, e error) {
+	for row, e := range DatacubeRows(obj, report, begin, end) {
+		if e != nil {
+			return nil, e
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func datacubeFetch[T any](obj *OfficialAccount, report DatacubeReport[T], w DatacubeWindow) (rows []*T, e error) {
+
+	resp := obj.Get(report.URI, w.Begin.Format(api.DatacubeTimeLayout), w.End.Format(api.DatacubeTimeLayout))
+	if e = resp.Error(); e != nil {
+		return nil, e
+	}
+	var result struct {
+		List []*T `json:"list"`
+	}
+	if e = resp.Unmarshal(&result); e != nil {
+		return nil, e
+	}
+	return result.List, nil
+}
+
+// DatacubeWriteCSV 将数据行写为CSV，表头为字段的json名，嵌套的列表字段以JSON写入
+func DatacubeWriteCSV[T any](w io.Writer, rows iter.Seq2[*T, error]) error {
+	var columns [][]int
+	var header []string
+	csvColumns(reflect.TypeFor[T](), nil, &columns, &header)
+
+	writer := csv.NewWriter(w)
+	if e := writer.Write(header); e != nil {
+		return e
+	}
+	record := make([]string, len(columns))
+	for row, e := range rows {
+		if e != nil {
+			return e
+		}
+		v := reflect.ValueOf(row).Elem()
+		for i, index := range columns {
+			if record[i], e = csvValue(v.FieldByIndex(index)); e != nil {
+				return e
+			}
+		}
+		if e = writer.Write(record); e != nil {
+			return e
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func csvColumns(t reflect.Type, index []int, columns *[][]int, header *[]string) {
+	for i := range t.NumField() {
+		f := t.Field(i)
+		idx := append(append([]int{}, index...), i)
+		if f.Anonymous && f.Type.Kind() == reflect.Struct {
+			csvColumns(f.Type, idx, columns, header)
+			continue
+		}
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if !f.IsExported() || name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		*columns = append(*columns, idx)
+		*header = append(*header, name)
+	}
+}
+
+func csvValue(v reflect.Value) (string, error) {
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 64), nil
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), nil
+	}
+	return jsoniter.MarshalToString(v.Interface())
+}
@@ -0,0 +1,53 @@
+package webox
+
+import (
+	"bytes"
+	"slices"
+	"testing"
+	"time"
+
+	"webox/model"
+)
+
+// TestDatacubeWindows ...
+func TestDatacubeWindows(t *testing.T) {
+	begin := time.Date(2024, 1, 1, 15, 0, 0, 0, time.Local)
+	end := time.Date(2024, 1, 16, 9, 0, 0, 0, time.Local)
+
+	var got []string
+	for _, w := range DatacubeWindows(begin, end, 7) {
+		got = append(got, w.Begin.Format("0102")+"-"+w.End.Format("0102"))
+	}
+	if want := []string{"0101-0107", "0108-0114", "0115-0116"}; !slices.Equal(got, want) {
+		t.Fatalf("unexpected windows %v", got)
+	}
+	if len(DatacubeWindows(end, begin, 7)) != 0 {
+		t.Fatal("expected no windows for an empty range")
+	}
+	for _, maxDays := range []int{0, -1} {
+		if windows := DatacubeWindows(begin, begin.AddDate(0, 0, 2), maxDays); len(windows) != 3 || !windows[2].Begin.Equal(windows[2].End) {
+			t.Fatalf("maxDays %d: unexpected windows %v", maxDays, windows)
+		}
+	}
+}
+
+// TestDatacubeWriteCSV ...
+func TestDatacubeWriteCSV(t *testing.T) {
+	rows := []*model.DatacubeUserRead{
+		{RefDate: "2024-01-01", UserSource: 99999999, DatacubeArticleRead: model.DatacubeArticleRead{IntPageReadUser: 3, ShareCount: 1}},
+	}
+	var buf bytes.Buffer
+	err := DatacubeWriteCSV(&buf, func(yield func(*model.DatacubeUserRead, error) bool) {
+		for _, row := range rows {
+			yield(row, nil)
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "ref_date,user_source,int_page_read_user,int_page_read_count,ori_page_read_user,ori_page_read_count,share_user,share_count,add_to_fav_user,add_to_fav_count\n" +
+		"2024-01-01,99999999,3,0,0,0,0,1,0,0\n"
+	if buf.String() != want {
+		t.Fatalf("unexpected csv %q", buf.String())
+	}
+}
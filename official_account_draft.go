@@ -0,0 +1,195 @@
+package webox
+
+import (
+	"context"
+	"webox/api"
+	"webox/model"
+	"webox/util"
+)
+
+// DraftAdd 新建草稿
+// http请求方式: POST
+// https://api.weixin.qq.com/cgi-bin/draft/add?access_token=ACCESS_TOKEN
+func (obj *OfficialAccount) DraftAdd(articles []*model.DraftArticle) (mediaID string, e error) {
+
+	u := util.URL(obj.RemoteURL(), api.DraftAdd)
+	resp := obj.Client().Post(context.Background(), u, nil, util.Map{"articles": articles})
+	if e = resp.Error(); e != nil {
+		return "", e
+	}
+	var result struct {
+		MediaID string `json:"media_id"`
+	}
+	if e = resp.Unmarshal(&result); e != nil {
+		return "", e
+	}
+	return result.MediaID, nil
+}
+
+// DraftGet 获取草稿
+// http请求方式: POST
+// https://api.weixin.qq.com/cgi-bin/draft/get?access_token=ACCESS_TOKEN
+func (obj *OfficialAccount) DraftGet(mediaID string) (articles []*model.DraftArticle, e error) {
+
+	u := util.URL(obj.RemoteURL(), api.DraftGet)
+	resp := obj.Client().Post(context.Background(), u, nil, util.Map{"media_id": mediaID})
+	if e = resp.Error(); e != nil {
+		return nil, e
+	}
+	var result model.DraftNews
+	if e = resp.Unmarshal(&result); e != nil {
+		return nil, e
+	}
+	return result.NewsItem, nil
+}
+
+// DraftDelete 删除草稿
+// http请求方式: POST
+// https://api.weixin.qq.com/cgi-bin/draft/delete?access_token=ACCESS_TOKEN
+func (obj *OfficialAccount) DraftDelete(mediaID string) Responder {
+
+	u := util.URL(obj.RemoteURL(), api.DraftDelete)
+	return obj.Client().Post(context.Background(), u, nil, util.Map{"media_id": mediaID})
+}
+
+// DraftUpdate 修改草稿
+// index: 要更新的文章在图文消息中的位置（多图文消息时，此字段才有意义），第一篇为0
+// http请求方式: POST
+// https://api.weixin.qq.com/cgi-bin/draft/update?access_token=ACCESS_TOKEN
+func (obj *OfficialAccount) DraftUpdate(mediaID string, index int, article *model.DraftArticle) Responder {
+
+	u := util.URL(obj.RemoteURL(), api.DraftUpdate)
+	return obj.Client().Post(context.Background(), u, nil, util.Map{
+		"media_id": mediaID,
+		"index":    index,
+		"articles": article,
+	})
+}
+
+// DraftCount 获取草稿总数
+// http请求方式: GET
+// https://api.weixin.qq.com/cgi-bin/draft/count?access_token=ACCESS_TOKEN
+func (obj *OfficialAccount) DraftCount() (count int, e error) {
+
+	u := util.URL(obj.RemoteURL(), api.DraftCount)
+	resp := obj.Client().Get(context.Background(), u, nil)
+	if e = resp.Error(); e != nil {
+		return 0, e
+	}
+	var result struct {
+		TotalCount int `json:"total_count"`
+	}
+	if e = resp.Unmarshal(&result); e != nil {
+		return 0, e
+	}
+	return result.TotalCount, nil
+}
+
+// DraftBatchGet 获取草稿列表
+// offset: 从全部素材的该偏移位置开始返回，0表示从第一个素材返回; count: 返回素材的数量，取值在1到20之间
+// noContent: 为true时不会返回content字段
+// http请求方式: POST
+// https://api.weixin.qq.com/cgi-bin/draft/batchget?access_token=ACCESS_TOKEN
+func (obj *OfficialAccount) DraftBatchGet(offset, count int, noContent bool) (list *model.DraftList, e error) {
+
+	u := util.URL(obj.RemoteURL(), api.DraftBatchGet)
+	resp := obj.Client().Post(context.Background(), u, nil, batchGetParams(offset, count, noContent))
+	if e = resp.Error(); e != nil {
+		return nil, e
+	}
+	list = new(model.DraftList)
+	if e = resp.Unmarshal(list); e != nil {
+		return nil, e
+	}
+	return list, nil
+}
+
+// FreePublishSubmit 发布接口
+// 发布任务提交成功后，发布结果将通过PUBLISHJOBFINISH事件推送（model.PublishJobFinishEvent）
+// http请求方式: POST
+// https://api.weixin.qq.com/cgi-bin/freepublish/submit?access_token=ACCESS_TOKEN
+func (obj *OfficialAccount) FreePublishSubmit(mediaID string) (result *model.FreePublishResult, e error) {
+
+	u := util.URL(obj.RemoteURL(), api.FreePublishSubmit)
+	resp := obj.Client().Post(context.Background(), u, nil, util.Map{"media_id": mediaID})
+	if e = resp.Error(); e != nil {
+		return nil, e
+	}
+	result = new(model.FreePublishResult)
+	if e = resp.Unmarshal(result); e != nil {
+		return nil, e
+	}
+	return result, nil
+}
+
+// FreePublishGet 发布状态轮询接口
+// http请求方式: POST
+// https://api.weixin.qq.com/cgi-bin/freepublish/get?access_token=ACCESS_TOKEN
+func (obj *OfficialAccount) FreePublishGet(publishID string) (info *model.PublishEventInfo, e error) {
+
+	u := util.URL(obj.RemoteURL(), api.FreePublishGet)
+	resp := obj.Client().Post(context.Background(), u, nil, util.Map{"publish_id": publishID})
+	if e = resp.Error(); e != nil {
+		return nil, e
+	}
+	info = new(model.PublishEventInfo)
+	if e = resp.Unmarshal(info); e != nil {
+		return nil, e
+	}
+	return info, nil
+}
+
+// FreePublishDelete 删除发布
+// index: 要删除的文章在图文消息中的位置，第一篇编号为1，该字段不填或填0会删除全部文章
+// http请求方式: POST
+// https://api.weixin.qq.com/cgi-bin/freepublish/delete?access_token=ACCESS_TOKEN
+func (obj *OfficialAccount) FreePublishDelete(articleID string, index int) Responder {
+
+	u := util.URL(obj.RemoteURL(), api.FreePublishDelete)
+	return obj.Client().Post(context.Background(), u, nil, util.Map{"article_id": articleID, "index": index})
+}
+
+// FreePublishGetArticle 通过 article_id 获取已发布文章
+// http请求方式: POST
+// https://api.weixin.qq.com/cgi-bin/freepublish/getarticle?access_token=ACCESS_TOKEN
+func (obj *OfficialAccount) FreePublishGetArticle(articleID string) (articles []*model.DraftArticle, e error) {
+
+	u := util.URL(obj.RemoteURL(), api.FreePublishGetArticle)
+	resp := obj.Client().Post(context.Background(), u, nil, util.Map{"article_id": articleID})
+	if e = resp.Error(); e != nil {
+		return nil, e
+	}
+	var result model.DraftNews
+	if e = resp.Unmarshal(&result); e != nil {
+		return nil, e
+	}
+	return result.NewsItem, nil
+}
+
+// FreePublishBatchGet 获取成功发布列表
+// http请求方式: POST
+// https://api.weixin.qq.com/cgi-bin/freepublish/batchget?access_token=ACCESS_TOKEN
+func (obj *OfficialAccount) FreePublishBatchGet(offset, count int, noContent bool) (list *model.FreePublishList, e error) {
+
+	u := util.URL(obj.RemoteURL(), api.FreePublishBatchGet)
+	resp := obj.Client().Post(context.Background(), u, nil, batchGetParams(offset, count, noContent))
+	if e = resp.Error(); e != nil {
+		return nil, e
+	}
+	list = new(model.FreePublishList)
+	if e = resp.Unmarshal(list); e != nil {
+		return nil, e
+	}
+	return list, nil
+}
+
+func batchGetParams(offset, count int, noContent bool) util.Map {
+	p := util.Map{
+		"offset": offset,
+		"count":  count,
+	}
+	if noContent {
+		p.Set("no_content", 1)
+	}
+	return p
+}
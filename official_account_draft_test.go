@@ -0,0 +1,95 @@
+package webox
+
+import (
+	"strings"
+	"testing"
+
+	"webox/api"
+	"webox/model"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// TestDraftArticle_Marshal 草稿接口中show_cover_pic为数值
+func TestDraftArticle_Marshal(t *testing.T) {
+	article := &model.DraftArticle{
+		Title:           "标题",
+		ThumbMediaID:    "thumb",
+		Content:         "<p>正文</p>",
+		ShowCoverPic:    1,
+		NeedOpenComment: 1,
+		PicCrop2351:     "0.1945_0_1_0.5236",
+	}
+	s, e := jsoniter.MarshalToString(article)
+	if e != nil {
+		t.Fatal(e)
+	}
+	if !jsonEqual(s, `{"title":"标题","thumb_media_id":"thumb","content":"<p>正文</p>","content_source_url":"",
+"need_open_comment":1,"show_cover_pic":1,"pic_crop_235_1":"0.1945_0_1_0.5236"}`) {
+		t.Fatalf("unexpected article json %s", s)
+	}
+	if strings.Count(s, "show_cover_pic") != 1 {
+		t.Fatalf("duplicated show_cover_pic in %s", s)
+	}
+}
+
+// TestOfficialAccount_DraftBatchGet ...
+func TestOfficialAccount_DraftBatchGet(t *testing.T) {
+	oa, requests := newTestOfficialAccount(t, map[string]string{
+		api.DraftBatchGet: `{"total_count":2,"item_count":1,"item":[{"media_id":"m1","update_time":1645000000,
+"content":{"news_item":[{"title":"标题","author":"作者","show_cover_pic":1,"url":"http://mp.weixin.qq.com/s/1",
+"thumb_url":"http://mmbiz.qpic.cn/1","need_open_comment":1,"only_fans_can_comment":0}]}}]}`,
+		api.FreePublishBatchGet: `{"total_count":1,"item_count":1,"item":[{"article_id":"a1","update_time":1645000001,
+"content":{"news_item":[{"title":"已发布","url":"http://mp.weixin.qq.com/s/2","is_deleted":true}]}}]}`,
+	})
+
+	drafts, e := oa.DraftBatchGet(0, 20, true)
+	if e != nil {
+		t.Fatal(e)
+	}
+	if drafts.TotalCount != 2 || len(drafts.Item) != 1 || drafts.Item[0].MediaID != "m1" || drafts.Item[0].UpdateTime != 1645000000 {
+		t.Fatalf("unexpected draft list %+v", drafts)
+	}
+	if news := drafts.Item[0].Content.NewsItem; len(news) != 1 || news[0].ShowCoverPic != 1 || news[0].Author != "作者" ||
+		news[0].URL != "http://mp.weixin.qq.com/s/1" || news[0].NeedOpenComment != 1 {
+		t.Fatalf("unexpected draft article %+v", news[0])
+	}
+
+	published, e := oa.FreePublishBatchGet(0, 20, false)
+	if e != nil {
+		t.Fatal(e)
+	}
+	if published.Item[0].ArticleID != "a1" || !published.Item[0].Content.NewsItem[0].IsDeleted {
+		t.Fatalf("unexpected publish list %+v", published.Item[0])
+	}
+
+	got := requests()
+	if !jsonEqual(got[0].Body, `{"offset":0,"count":20,"no_content":1}`) || !jsonEqual(got[1].Body, `{"offset":0,"count":20}`) {
+		t.Fatalf("unexpected batchget bodies %s %s", got[0].Body, got[1].Body)
+	}
+}
+
+// TestOfficialAccount_FreePublish ...
+func TestOfficialAccount_FreePublish(t *testing.T) {
+	oa, requests := newTestOfficialAccount(t, map[string]string{
+		api.FreePublishSubmit: `{"errcode":0,"errmsg":"ok","publish_id":"100000001","msg_data_id":2247483650}`,
+		api.FreePublishGet: `{"publish_id":"100000001","publish_status":0,"article_id":"a1",
+"article_detail":{"count":1,"item":[{"idx":1,"article_url":"http://mp.weixin.qq.com/s/1"}]},"fail_idx":[]}`,
+	})
+
+	result, e := oa.FreePublishSubmit("m1")
+	if e != nil || result.PublishID != "100000001" || result.MsgDataID != 2247483650 {
+		t.Fatalf("unexpected submit result %+v %v", result, e)
+	}
+	info, e := oa.FreePublishGet(result.PublishID)
+	if e != nil {
+		t.Fatal(e)
+	}
+	if info.PublishStatus != model.PublishStatusSuccess || info.ArticleID != "a1" || info.ArticleDetail.Count != 1 ||
+		info.ArticleDetail.Item[0].ArticleURL != "http://mp.weixin.qq.com/s/1" {
+		t.Fatalf("unexpected publish info %+v", info)
+	}
+	if got := requests(); !jsonEqual(got[1].Body, `{"publish_id":"100000001"}`) {
+		t.Fatalf("unexpected publish get body %s", got[1].Body)
+	}
+}
@@ -0,0 +1,242 @@
+package webox
+
+import (
+	"context"
+	"iter"
+	"time"
+	"webox/api"
+	"webox/model"
+	"webox/util"
+)
+
+// KfMsgRecordMaxNumber 获取聊天记录每次最多拉取的条数
+const KfMsgRecordMaxNumber = 10000
+
+// KfAccountAdd 添加客服帐号
+// http请求方式: POST
+// https://api.weixin.qq.com/customservice/kfaccount/add?access_token=ACCESS_TOKEN
+// POST数据示例:
+// {"kf_account":"test1@test","nickname":"客服1"}
+func (obj *OfficialAccount) KfAccountAdd(account, nickname string) Responder {
+
+	u := util.URL(obj.RemoteURL(), api.KfAccountAdd)
+	return obj.Client().Post(context.Background(), u, nil, util.Map{"kf_account": account, "nickname": nickname})
+}
+
+// KfAccountUpdate 设置客服信息
+// http请求方式: POST
+// https://api.weixin.qq.com/customservice/kfaccount/update?access_token=ACCESS_TOKEN
+func (obj *OfficialAccount) KfAccountUpdate(account, nickname string) Responder {
+
+	u := util.URL(obj.RemoteURL(), api.KfAccountUpdate)
+	return obj.Client().Post(context.Background(), u, nil, util.Map{"kf_account": account, "nickname": nickname})
+}
+
+// KfAccountDel 删除客服帐号
+// http请求方式: GET
+// https://api.weixin.qq.com/customservice/kfaccount/del?access_token=ACCESS_TOKEN&kf_account=KFACCOUNT
+func (obj *OfficialAccount) KfAccountDel(account string) Responder {
+
+	u := util.URL(obj.RemoteURL(), api.KfAccountDel)
+	return obj.Client().Get(context.Background(), u, util.Map{"kf_account": account})
+}
+
+// KfAccountUploadHeadImg 上传客服头像
+// 头像图片文件必须是jpg格式，推荐使用640*640大小的图片以达到最佳效果
+// http请求方式: POST/FORM
+// https://api.weixin.qq.com/customservice/kfaccount/uploadheadimg?access_token=ACCESS_TOKEN&kf_account=KFACCOUNT
+// 调用示例:使用curl命令，用FORM表单方式上传一个多媒体文件，curl命令的具体用法请自行了解
+func (obj *OfficialAccount) KfAccountUploadHeadImg(account, filePath string) Responder {
+
+	u := util.URL(obj.RemoteURL(), api.KfAccountUploadHeadImg)
+	return obj.Client().Upload(context.Background(), u, util.Map{"kf_account": account}, util.Map{"media": filePath})
+}
+
+// KfAccountInviteWorker 邀请绑定客服帐号
+// 新添加的客服帐号是不能直接使用的，只有客服人员用微信号绑定了客服账号后，方可登录Web客服进行操作。
+// 此接口发起一个绑定邀请到客服人员微信号，客服人员需要在微信客户端上用该微信号确认后帐号才可用。
+// http请求方式: POST
+// https://api.weixin.qq.com/customservice/kfaccount/inviteworker?access_token=ACCESS_TOKEN
+// POST数据示例:
+// {"kf_account":"test1@test","invite_wx":"test_kfwx"}
+func (obj *OfficialAccount) KfAccountInviteWorker(account, inviteWx string) Responder {
+
+	u := util.URL(obj.RemoteURL(), api.KfAccountInviteWorker)
+	return obj.Client().Post(context.Background(), u, nil, util.Map{"kf_account": account, "invite_wx": inviteWx})
+}
+
+// KfList 获取所有客服账号
+// http请求方式: GET
+// https://api.weixin.qq.com/cgi-bin/customservice/getkflist?access_token=ACCESS_TOKEN
+func (obj *OfficialAccount) KfList() (list []*model.KfInfo, e error) {
+
+	u := util.URL(obj.RemoteURL(), api.GetKFList)
+	resp := obj.Client().Get(context.Background(), u, nil)
+	if e = resp.Error(); e != nil {
+		return nil, e
+	}
+	var result model.KfList
+	if e = resp.Unmarshal(&result); e != nil {
+		return nil, e
+	}
+	return result.KfList, nil
+}
+
+// KfOnlineList 获取在线客服
+// http请求方式: GET
+// https://api.weixin.qq.com/cgi-bin/customservice/getonlinekflist?access_token=ACCESS_TOKEN
+func (obj *OfficialAccount) KfOnlineList() (list []*model.KfOnlineInfo, e error) {
+
+	u := util.URL(obj.RemoteURL(), api.GetOnlineKFList)
+	resp := obj.Client().Get(context.Background(), u, nil)
+	if e = resp.Error(); e != nil {
+		return nil, e
+	}
+	var result model.KfOnlineList
+	if e = resp.Unmarshal(&result); e != nil {
+		return nil, e
+	}
+	return result.KfOnlineList, nil
+}
+
+// KfSessionCreate 创建会话
+// 此接口在客服和用户之间创建一个会话，如果该客服和用户会话已存在，则直接返回0。指定的客服帐号必须已经绑定微信号且在线。
+// http请求方式: POST
+// https://api.weixin.qq.com/customservice/kfsession/create?access_token=ACCESS_TOKEN
+func (obj *OfficialAccount) KfSessionCreate(account, openid string) Responder {
+
+	u := util.URL(obj.RemoteURL(), api.KfSessionCreate)
+	return obj.Client().Post(context.Background(), u, nil, util.Map{"kf_account": account, "openid": openid})
+}
+
+// KfSessionClose 关闭会话
+// http请求方式: POST
+// https://api.weixin.qq.com/customservice/kfsession/close?access_token=ACCESS_TOKEN
+func (obj *OfficialAccount) KfSessionClose(account, openid string) Responder {
+
+	u := util.URL(obj.RemoteURL(), api.KfSessionClose)
+	return obj.Client().Post(context.Background(), u, nil, util.Map{"kf_account": account, "openid": openid})
+}
+
+// KfSessionGet 获取客户会话状态
+// http请求方式: GET
+// https://api.weixin.qq.com/customservice/kfsession/getsession?access_token=ACCESS_TOKEN&openid=OPENID
+func (obj *OfficialAccount) KfSessionGet(openid string) (session *model.KfSession, e error) {
+
+	u := util.URL(obj.RemoteURL(), api.KfSessionGet)
+	resp := obj.Client().Get(context.Background(), u, util.Map{"openid": openid})
+	if e = resp.Error(); e != nil {
+		return nil, e
+	}
+	session = new(model.KfSession)
+	if e = resp.Unmarshal(session); e != nil {
+		return nil, e
+	}
+	session.OpenID = openid
+	return session, nil
+}
+
+// KfSessionList 获取客服会话列表
+// http请求方式: GET
+// https://api.weixin.qq.com/customservice/kfsession/getsessionlist?access_token=ACCESS_TOKEN&kf_account=KFACCOUNT
+func (obj *OfficialAccount) KfSessionList(account string) (list []*model.KfSession, e error) {
+
+	u := util.URL(obj.RemoteURL(), api.KfSessionGetList)
+	resp := obj.Client().Get(context.Background(), u, util.Map{"kf_account": account})
+	if e = resp.Error(); e != nil {
+		return nil, e
+	}
+	var result model.KfSessionList
+	if e = resp.Unmarshal(&result); e != nil {
+		return nil, e
+	}
+	return result.SessionList, nil
+}
+
+// KfSessionWaitCase 获取未接入会话列表
+// http请求方式: GET
+// https://api.weixin.qq.com/customservice/kfsession/getwaitcase?access_token=ACCESS_TOKEN
+func (obj *OfficialAccount) KfSessionWaitCase() (wait *model.KfWaitCase, e error) {
+
+	u := util.URL(obj.RemoteURL(), api.KfSessionGetWaitCase)
+	resp := obj.Client().Get(context.Background(), u, nil)
+	if e = resp.Error(); e != nil {
+		return nil, e
+	}
+	wait = new(model.KfWaitCase)
+	if e = resp.Unmarshal(wait); e != nil {
+		return nil, e
+	}
+	return wait, nil
+}
+
+// KfMsgRecordList 获取聊天记录
+// 此接口返回的聊天记录中，对于图片、语音、视频，分别展示成文本格式的[image]、[voice]、[video]。
+// 起始时间和结束时间需在同一天，msgid为消息id顺序从小到大，从1开始，number每次获取条数，最多10000条
+// http请求方式: POST
+// https://api.weixin.qq.com/customservice/msgrecord/getmsglist?access_token=ACCESS_TOKEN
+func (obj *OfficialAccount) KfMsgRecordList(start, end time.Time, msgID int64, number int) (list *model.KfMsgRecordList, e error) {
+
+	u := util.URL(obj.RemoteURL(), api.KfMsgRecordList)
+	resp := obj.Client().Post(context.Background(), u, nil, util.Map{
+		"starttime": start.Unix(),
+		"endtime":   end.Unix(),
+		"msgid":     msgID,
+		"number":    number,
+	})
+	if e = resp.Error(); e != nil {
+		return nil, e
+	}
+	list = new(model.KfMsgRecordList)
+	if e = resp.Unmarshal(list); e != nil {
+		return nil, e
+	}
+	return list, nil
+}
+
+// KfMsgRecords 按msgid翻页遍历[start,end]内的全部聊天记录
+func (obj *OfficialAccount) KfMsgRecords(start, end time.Time) iter.Seq2[*model.KfMsgRecord, error] {
+	return func(yield func(*model.KfMsgRecord, error) bool) {
+		msgID := int64(1)
+		for {
+			list, e := obj.KfMsgRecordList(start, end, msgID, KfMsgRecordMaxNumber)
+			if e != nil {
+				yield(nil, e)
+				return
+			}
+			for _, record := range list.RecordList {
+				if !yield(record, nil) {
+					return
+				}
+			}
+			if list.Number < KfMsgRecordMaxNumber || list.MsgID <= msgID {
+				return
+			}
+			msgID = list.MsgID
+		}
+	}
+}
+
+// MessageCustomSend 发送客服消息
+// 当用户和公众号产生特定动作的交互时，微信将会把消息数据推送给开发者，开发者可以在一段时间内（目前修改为48小时）调用客服接口，发送消息给普通用户。
+// http请求方式: POST
+// https://api.weixin.qq.com/cgi-bin/message/custom/send?access_token=ACCESS_TOKEN
+func (obj *OfficialAccount) MessageCustomSend(msg *model.CustomMessage) Responder {
+
+	u := util.URL(obj.RemoteURL(), api.SendMessageCustom)
+	return obj.Client().Post(context.Background(), u, nil, msg)
+}
+
+// MessageCustomTyping 客服输入状态
+// command: Typing 对用户下发"正在输入"状态, CancelTyping 取消对用户的"正在输入"状态
+// http请求方式: POST
+// https://api.weixin.qq.com/cgi-bin/message/custom/typing?access_token=ACCESS_TOKEN
+func (obj *OfficialAccount) MessageCustomTyping(toUser string, typing bool) Responder {
+
+	command := "CancelTyping"
+	if typing {
+		command = "Typing"
+	}
+	u := util.URL(obj.RemoteURL(), api.MessageCustomTyping)
+	return obj.Client().Post(context.Background(), u, nil, util.Map{"touser": toUser, "command": command})
+}
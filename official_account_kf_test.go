@@ -0,0 +1,145 @@
+package webox
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"webox/api"
+	"webox/model"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// testRequest 测试服务端收到的请求
+type testRequest struct {
+	Method string
+	Path   string
+	Query  map[string]string
+	Body   string
+}
+
+// newTestOfficialAccount 返回指向测试服务端的公众号，routes按路径返回固定的响应
+func newTestOfficialAccount(t *testing.T, routes map[string]string) (*OfficialAccount, func() []testRequest) {
+	t.Helper()
+	var mu sync.Mutex
+	var requests []testRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		query := make(map[string]string)
+		for k := range r.URL.Query() {
+			query[k] = r.URL.Query().Get(k)
+		}
+		mu.Lock()
+		requests = append(requests, testRequest{Method: r.Method, Path: r.URL.Path, Query: query, Body: string(body)})
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		if resp, ok := routes[r.URL.Path]; ok {
+			_, _ = w.Write([]byte(resp))
+			return
+		}
+		_, _ = w.Write([]byte(`{"errcode":0,"errmsg":"ok"}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	return testOfficialAccountAt(srv.URL), func() []testRequest {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]testRequest(nil), requests...)
+	}
+}
+
+// testOfficialAccountAt 返回请求remote并使用固定token的公众号
+func testOfficialAccountAt(remote string) *OfficialAccount {
+	return NewOfficialAccount(&OfficialAccountProperty{AppID: "wx", AppSecret: "secret"},
+		OfficialAccountRemote(remote),
+		OfficialAccountAccessToken(NewAccessToken(&AccessTokenProperty{}, AccessTokenProvider(NewStaticTokenProvider("token")))))
+}
+
+// jsonEqual 比较两个json是否等价，忽略map的键顺序
+func jsonEqual(a, b string) bool {
+	var va, vb any
+	if jsoniter.UnmarshalFromString(a, &va) != nil || jsoniter.UnmarshalFromString(b, &vb) != nil {
+		return false
+	}
+	return reflect.DeepEqual(va, vb)
+}
+
+// TestCustomMessage_Marshal ...
+func TestCustomMessage_Marshal(t *testing.T) {
+	for _, c := range []struct {
+		msg  *model.CustomMessage
+		want string
+	}{
+		{model.NewCustomText("o1", "hello"), `{"touser":"o1","msgtype":"text","text":{"content":"hello"}}`},
+		{model.NewCustomImage("o1", "m1").WithKfAccount("kf@test"),
+			`{"touser":"o1","msgtype":"image","image":{"media_id":"m1"},"customservice":{"kf_account":"kf@test"}}`},
+		{model.NewCustomNews("o1", &model.CustomArticle{Title: "t", Description: "d", URL: "u", PicURL: "p"}),
+			`{"touser":"o1","msgtype":"news","news":{"articles":[{"title":"t","description":"d","url":"u","picurl":"p"}]}}`},
+		{model.NewCustomMPNewsArticle("o1", "a1"), `{"touser":"o1","msgtype":"mpnewsarticle","mpnewsarticle":{"article_id":"a1"}}`},
+		{model.NewCustomMsgMenu("o1", &model.CustomMsgMenu{HeadContent: "h", List: []*model.CustomMenuItem{{ID: "101", Content: "满意"}}, TailContent: "t"}),
+			`{"touser":"o1","msgtype":"msgmenu","msgmenu":{"head_content":"h","list":[{"id":"101","content":"满意"}],"tail_content":"t"}}`},
+		{model.NewCustomWxCard("o1", "c1"), `{"touser":"o1","msgtype":"wxcard","wxcard":{"card_id":"c1"}}`},
+	} {
+		s, _ := jsoniter.MarshalToString(c.msg)
+		if s != c.want {
+			t.Errorf("got %s, want %s", s, c.want)
+		}
+	}
+}
+
+// TestOfficialAccount_Kf ...
+func TestOfficialAccount_Kf(t *testing.T) {
+	oa, requests := newTestOfficialAccount(t, map[string]string{
+		api.GetKFList:    `{"kf_list":[{"kf_account":"test1@test","kf_nick":"ntest1","kf_id":"1001","kf_headimgurl":"http://mmbiz.qpic.cn/1"}]}`,
+		api.KfSessionGet: `{"createtime":123456789,"kf_account":"test1@test"}`,
+		api.KfMsgRecordList: `{"recordlist":[{"openid":"o1","opercode":2002,"text":"您好","time":150,"worker":"test1@test"}],
+"number":1,"msgid":20165267}`,
+	})
+
+	if e := oa.KfAccountAdd("test1@test", "客服1").Error(); e != nil {
+		t.Fatal(e)
+	}
+	list, e := oa.KfList()
+	if e != nil || len(list) != 1 || list[0].KfID != "1001" || list[0].KfNick != "ntest1" {
+		t.Fatalf("unexpected kf list %v %v", list, e)
+	}
+	session, e := oa.KfSessionGet("o1")
+	if e != nil || session.KfAccount != "test1@test" || session.CreateTime != 123456789 || session.OpenID != "o1" {
+		t.Fatalf("unexpected session %+v %v", session, e)
+	}
+	records, e := oa.KfMsgRecordList(time.Unix(100, 0), time.Unix(200, 0), 1, 10)
+	if e != nil || records.Number != 1 || records.MsgID != 20165267 || records.RecordList[0].OperCode != 2002 {
+		t.Fatalf("unexpected records %+v %v", records, e)
+	}
+	if e := oa.MessageCustomSend(model.NewCustomText("o1", "hello")).Error(); e != nil {
+		t.Fatal(e)
+	}
+
+	want := []testRequest{
+		{Method: http.MethodPost, Path: api.KfAccountAdd, Body: `{"kf_account":"test1@test","nickname":"客服1"}`},
+		{Method: http.MethodGet, Path: api.GetKFList},
+		{Method: http.MethodGet, Path: api.KfSessionGet},
+		{Method: http.MethodPost, Path: api.KfMsgRecordList, Body: `{"endtime":200,"msgid":1,"number":10,"starttime":100}`},
+		{Method: http.MethodPost, Path: api.SendMessageCustom, Body: `{"touser":"o1","msgtype":"text","text":{"content":"hello"}}`},
+	}
+	got := requests()
+	if len(got) != len(want) {
+		t.Fatalf("got %d requests, want %d", len(got), len(want))
+	}
+	for i, r := range got {
+		if r.Method != want[i].Method || r.Path != want[i].Path || r.Query[api.AccessTokenKey] != "token" {
+			t.Errorf("request %d: got %s %s %v", i, r.Method, r.Path, r.Query)
+		}
+		if want[i].Body != "" && !jsonEqual(r.Body, want[i].Body) {
+			t.Errorf("request %d: got body %s, want %s", i, r.Body, want[i].Body)
+		}
+	}
+	if got[2].Query["openid"] != "o1" {
+		t.Errorf("unexpected session query %v", got[2].Query)
+	}
+}
@@ -0,0 +1,155 @@
+package webox
+
+import (
+	"context"
+	"strconv"
+	"webox/api"
+	"webox/model"
+	"webox/util"
+)
+
+// MassSend 群发消息
+// target为全部用户或标签时调用sendall接口；为OpenID列表时调用send接口，列表超过10000个时将拆分为多次调用，
+// 此时若设置了clientmsgid，则每次调用使用“clientmsgid_序号”以保证各批次独立去重。
+// target为nil、类型未知或OpenID列表为空时返回错误；出错时返回已成功批次的结果与错误
+// http请求方式: POST
+// https://api.weixin.qq.com/cgi-bin/message/mass/sendall?access_token=ACCESS_TOKEN
+// https://api.weixin.qq.com/cgi-bin/message/mass/send?access_token=ACCESS_TOKEN
+func (obj *OfficialAccount) MassSend(msg *model.MassMessage, target *model.MassTarget) (results []*model.MassResult, e error) {
+
+	if e = target.Validate(); e != nil {
+		return nil, e
+	}
+	if target.Kind != model.MassTargetOpenIDs {
+		p, e := massParams(msg, "")
+		if e != nil {
+			return nil, e
+		}
+		filter := util.Map{"is_to_all": target.Kind == model.MassTargetAll}
+		if target.Kind == model.MassTargetTag {
+			filter.Set("tag_id", target.TagID)
+		}
+		p.Set("filter", filter)
+		result, e := obj.massSend(api.MessageMassSendall, p)
+		if e != nil {
+			return nil, e
+		}
+		return []*model.MassResult{result}, nil
+	}
+
+	chunks := target.Chunks()
+	for i, openids := range chunks {
+		suffix := ""
+		if len(chunks) > 1 {
+			suffix = "_" + strconv.Itoa(i)
+		}
+		p, e := massParams(msg, suffix)
+		if e != nil {
+			return results, e
+		}
+		p.Set("touser", openids)
+		result, e := obj.massSend(api.SendMessageMass, p)
+		if e != nil {
+			return results, e
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+func (obj *OfficialAccount) massSend(uri string, p util.Map) (result *model.MassResult, e error) {
+
+	resp := obj.Client().Post(context.Background(), util.URL(obj.RemoteURL(), uri), nil, p)
+	if e = resp.Error(); e != nil {
+		return nil, e
+	}
+	result = new(model.MassResult)
+	if e = resp.Unmarshal(result); e != nil {
+		return nil, e
+	}
+	return result, nil
+}
+
+func massParams(msg *model.MassMessage, suffix string) (util.Map, error) {
+	p := make(util.Map)
+	if e := util.StructToMap(msg, p); e != nil {
+		return nil, e
+	}
+	if msg.ClientMsgID != "" && suffix != "" {
+		p.Set("clientmsgid", msg.ClientMsgID+suffix)
+	}
+	return p, nil
+}
+
+// MassPreview 预览群发消息
+// toUser为接收消息用户的openid；wxName不为空时按微信号发送预览（优先级高于toUser，每日限100次）
+// http请求方式: POST
+// https://api.weixin.qq.com/cgi-bin/message/mass/preview?access_token=ACCESS_TOKEN
+func (obj *OfficialAccount) MassPreview(msg *model.MassMessage, toUser, wxName string) Responder {
+
+	p, e := massParams(msg, "")
+	if e != nil {
+		return ErrResponder(e)
+	}
+	p.Delete("send_ignore_reprint")
+	p.Delete("clientmsgid")
+	p.Set("touser", toUser)
+	if wxName != "" {
+		p.Set("towxname", wxName)
+	}
+	return obj.MessagePreview(p)
+}
+
+// MassGet 查询群发消息发送状态
+// http请求方式: POST
+// https://api.weixin.qq.com/cgi-bin/message/mass/get?access_token=ACCESS_TOKEN
+func (obj *OfficialAccount) MassGet(msgID int64) (status *model.MassStatus, e error) {
+
+	u := util.URL(obj.RemoteURL(), api.GetMessageMass)
+	resp := obj.Client().Post(context.Background(), u, nil, util.Map{"msg_id": strconv.FormatInt(msgID, 10)})
+	if e = resp.Error(); e != nil {
+		return nil, e
+	}
+	status = new(model.MassStatus)
+	if e = resp.Unmarshal(status); e != nil {
+		return nil, e
+	}
+	return status, nil
+}
+
+// MassDelete 删除群发
+// articleIdx: 要删除的文章在图文消息中的位置，第一篇编号为1，该字段不填或填0会删除全部文章
+// http请求方式: POST
+// https://api.weixin.qq.com/cgi-bin/message/mass/delete?access_token=ACCESS_TOKEN
+func (obj *OfficialAccount) MassDelete(msgID int64, articleIdx int) Responder {
+
+	u := util.URL(obj.RemoteURL(), api.DeleteMessageMass)
+	return obj.Client().Post(context.Background(), u, nil, util.Map{"msg_id": msgID, "article_idx": articleIdx})
+}
+
+// MassSpeedGet 获取群发速度
+// http请求方式: POST
+// https://api.weixin.qq.com/cgi-bin/message/mass/speed/get?access_token=ACCESS_TOKEN
+func (obj *OfficialAccount) MassSpeedGet() (speed *model.MassSpeed, e error) {
+
+	u := util.URL(obj.RemoteURL(), api.MessageMassSpeedGet)
+	resp := obj.Client().Post(context.Background(), u, nil, util.Map{})
+	if e = resp.Error(); e != nil {
+		return nil, e
+	}
+	speed = new(model.MassSpeed)
+	if e = resp.Unmarshal(speed); e != nil {
+		return nil, e
+	}
+	return speed, nil
+}
+
+// MassSpeedSet 设置群发速度
+// speed: 群发速度的级别，0:80w/分钟 1:60w/分钟 2:45w/分钟 3:30w/分钟 4:10w/分钟
+// http请求方式: POST
+// https://api.weixin.qq.com/cgi-bin/message/mass/speed/set?access_token=ACCESS_TOKEN
+func (obj *OfficialAccount) MassSpeedSet(speed int) Responder {
+
+	u := util.URL(obj.RemoteURL(), api.MessageMassSpeedSet)
+	return obj.Client().Post(context.Background(), u, nil, util.Map{"speed": speed})
+}
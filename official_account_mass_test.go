@@ -0,0 +1,100 @@
+package webox
+
+import (
+	"fmt"
+	"testing"
+
+	"webox/api"
+	"webox/model"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+func testOpenIDs(n int) []string {
+	ids := make([]string, n)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("o%d", i)
+	}
+	return ids
+}
+
+// TestMassTarget_Chunks 按上限拆分OpenID列表，最后一组不少于两个
+func TestMassTarget_Chunks(t *testing.T) {
+	for n, want := range map[int][]int{
+		0:     nil,
+		2:     {2},
+		10000: {10000},
+		10001: {9999, 2},
+		20001: {10000, 9999, 2},
+		25000: {10000, 10000, 5000},
+	} {
+		ids := testOpenIDs(n)
+		chunks := model.MassToOpenIDs(ids...).Chunks()
+		if len(chunks) != len(want) {
+			t.Fatalf("%d openids: got %d chunks, want %d", n, len(chunks), len(want))
+		}
+		total := 0
+		for i, chunk := range chunks {
+			if len(chunk) != want[i] {
+				t.Fatalf("%d openids: chunk %d has %d openids, want %d", n, i, len(chunk), want[i])
+			}
+			for j, id := range chunk {
+				if id != ids[total+j] {
+					t.Fatalf("%d openids: chunk %d out of order at %d", n, i, j)
+				}
+			}
+			total += len(chunk)
+		}
+	}
+}
+
+// TestOfficialAccount_MassSend ...
+func TestOfficialAccount_MassSend(t *testing.T) {
+	oa, requests := newTestOfficialAccount(t, map[string]string{
+		api.MessageMassSendall: `{"errcode":0,"errmsg":"send job submission success","msg_id":1000000001}`,
+		api.SendMessageMass:    `{"errcode":0,"errmsg":"send job submission success","msg_id":1000000002}`,
+	})
+	msg := model.NewMassText("hello").WithClientMsgID("job")
+
+	for _, target := range []*model.MassTarget{nil, model.MassToOpenIDs(), model.MassToOpenIDs([]string(nil)...), model.MassToOpenIDs("one"), {TagID: 1}} {
+		if _, e := oa.MassSend(msg, target); e == nil {
+			t.Fatalf("expected error for target %+v", target)
+		}
+	}
+	if n := len(requests()); n != 0 {
+		t.Fatalf("invalid targets sent %d requests", n)
+	}
+
+	results, e := oa.MassSend(msg, model.MassToTag(0))
+	if e != nil || len(results) != 1 || results[0].MsgID != 1000000001 {
+		t.Fatalf("unexpected tag results %v %v", results, e)
+	}
+	if got := requests()[0]; got.Path != api.MessageMassSendall || !jsonEqual(got.Body,
+		`{"msgtype":"text","text":{"content":"hello"},"send_ignore_reprint":0,"clientmsgid":"job","filter":{"is_to_all":false,"tag_id":0}}`) {
+		t.Fatalf("unexpected sendall request %s %s", got.Path, got.Body)
+	}
+
+	results, e = oa.MassSend(msg, model.MassToOpenIDs(testOpenIDs(10001)...))
+	if e != nil || len(results) != 2 {
+		t.Fatalf("unexpected openid results %v %v", results, e)
+	}
+	for i, got := range requests()[1:] {
+		var body struct {
+			ToUser      []string `json:"touser"`
+			ClientMsgID string   `json:"clientmsgid"`
+		}
+		if e := jsoniter.UnmarshalFromString(got.Body, &body); e != nil {
+			t.Fatal(e)
+		}
+		if got.Path != api.SendMessageMass || body.ClientMsgID != fmt.Sprintf("job_%d", i) || len(body.ToUser) != []int{9999, 2}[i] {
+			t.Fatalf("request %d: unexpected %s clientmsgid %q with %d openids", i, got.Path, body.ClientMsgID, len(body.ToUser))
+		}
+	}
+
+	if _, e = oa.MassSend(msg, model.MassToOpenIDs("o1", "o2")); e != nil {
+		t.Fatal(e)
+	}
+	if got := requests()[3]; !jsonEqual(got.Body, `{"msgtype":"text","text":{"content":"hello"},"send_ignore_reprint":0,"clientmsgid":"job","touser":["o1","o2"]}`) {
+		t.Fatalf("single batch should keep clientmsgid, got %s", got.Body)
+	}
+}
@@ -0,0 +1,291 @@
+package webox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"webox/api"
+	"webox/model"
+	"webox/util"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// MaterialBatchGetMaxCount 获取素材列表每次最多返回的素材数量
+const MaterialBatchGetMaxCount = 20
+
+// MaterialMirrorManifest 素材镜像目录中的清单文件名
+const MaterialMirrorManifest = "manifest.json"
+
+// MaterialCount 获取素材总数
+// http请求方式: GET
+// https://api.weixin.qq.com/cgi-bin/material/get_materialcount?access_token=ACCESS_TOKEN
+func (obj *OfficialAccount) MaterialCount() (count *model.MaterialCount, e error) {
+
+	resp := obj.MaterialGetCount()
+	if e = resp.Error(); e != nil {
+		return nil, e
+	}
+	count = new(model.MaterialCount)
+	if e = resp.Unmarshal(count); e != nil {
+		return nil, e
+	}
+	return count, nil
+}
+
+// MaterialList 获取素材列表
+// http请求方式: POST
+// https://api.weixin.qq.com/cgi-bin/material/batchget_material?access_token=ACCESS_TOKEN
+func (obj *OfficialAccount) MaterialList(mediaType MediaType, offset, count int) (list *model.MaterialList, e error) {
+
+	resp := obj.MaterialBatchGet(mediaType, offset, count)
+	if e = resp.Error(); e != nil {
+		return nil, e
+	}
+	list = new(model.MaterialList)
+	if e = resp.Unmarshal(list); e != nil {
+		return nil, e
+	}
+	return list, nil
+}
+
+// Materials 遍历指定类型的全部永久素材
+func (obj *OfficialAccount) Materials(mediaType MediaType) iter.Seq2[*model.MaterialItem, error] {
+	return func(yield func(*model.MaterialItem, error) bool) {
+		offset := 0
+		for {
+			list, e := obj.MaterialList(mediaType, offset, MaterialBatchGetMaxCount)
+			if e != nil {
+				yield(nil, e)
+				return
+			}
+			for _, item := range list.Item {
+				if !yield(item, nil) {
+					return
+				}
+			}
+			offset += list.ItemCount
+			if list.ItemCount == 0 || offset >= list.TotalCount {
+				return
+			}
+		}
+	}
+}
+
+// MaterialGetTo 获取永久素材
+// 图片、语音等二进制素材直接写入w并返回nil；图文和视频素材返回其内容，不写入w
+// http请求方式: POST,https协议
+// https://api.weixin.qq.com/cgi-bin/material/get_material?access_token=ACCESS_TOKEN
+func (obj *OfficialAccount) MaterialGetTo(mediaID string, w io.Writer) (content *model.MaterialContent, e error) {
+
+	u := util.URL(obj.RemoteURL(), api.GetMaterial)
+	resp, e := obj.Client().Stream(context.Background(), api.POST, u, nil, util.Map{"media_id": mediaID})
+	if e != nil {
+		return nil, e
+	}
+	r, e := StreamTo(resp, w)
+	if e != nil || r == nil {
+		return nil, e
+	}
+	if e = r.Error(); e != nil {
+		return nil, e
+	}
+	content = new(model.MaterialContent)
+	if e = r.Unmarshal(content); e != nil {
+		return nil, e
+	}
+	return content, nil
+}
+
+// MaterialManifestEntry 已镜像的素材，File为相对镜像目录的路径
+type MaterialManifestEntry struct {
+	MediaID     string    `json:"media_id"`
+	Type        MediaType `json:"type"`
+	Name        string    `json:"name,omitempty"`
+	Title       string    `json:"title,omitempty"`
+	Description string    `json:"description,omitempty"`
+	UpdateTime  int64     `json:"update_time"`
+	File        string    `json:"file"`
+}
+
+// MaterialManifest 素材镜像清单
+type MaterialManifest struct {
+	Items map[string]*MaterialManifestEntry `json:"items"`
+}
+
+// MaterialMirror 将永久素材下载到本地目录，并在目录下维护清单文件
+// 重复执行时只下载新增或更新过的素材，并删除已不存在的素材；二进制素材直接写入文件，不在内存中缓存。
+// types为空时镜像全部类型
+func (obj *OfficialAccount) MaterialMirror(dir string, types ...MediaType) (manifest *MaterialManifest, e error) {
+	if len(types) == 0 {
+		types = []MediaType{MediaTypeImage, MediaTypeVoice, MediaTypeVideo, MediaTypeNews}
+	}
+	if manifest, e = loadMaterialManifest(dir); e != nil {
+		return nil, e
+	}
+	defer func() {
+		if err := writeFileAtomic(filepath.Join(dir, MaterialMirrorManifest), func(w io.Writer) error {
+			return jsoniter.NewEncoder(w).Encode(manifest)
+		}); e == nil {
+			e = err
+		}
+	}()
+
+	seen := make(map[string]bool)
+	for _, mediaType := range types {
+		for item, e := range obj.Materials(mediaType) {
+			if e != nil {
+				return manifest, e
+			}
+			seen[item.MediaID] = true
+			if old, ok := manifest.Items[item.MediaID]; ok && old.UpdateTime == item.UpdateTime {
+				if _, err := os.Stat(filepath.Join(dir, old.File)); err == nil {
+					continue
+				}
+			}
+			entry, e := obj.mirrorMaterial(dir, mediaType, item)
+			if e != nil {
+				return manifest, e
+			}
+			manifest.Items[item.MediaID] = entry
+		}
+	}
+
+	for id, entry := range manifest.Items {
+		if seen[id] || !slices.Contains(types, entry.Type) {
+			continue
+		}
+		path, err := mirrorPath(dir, entry.File)
+		if err != nil {
+			return manifest, err
+		}
+		if err = os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return manifest, err
+		}
+		delete(manifest.Items, id)
+	}
+	return manifest, nil
+}
+
+func (obj *OfficialAccount) mirrorMaterial(dir string, mediaType MediaType, item *model.MaterialItem) (*MaterialManifestEntry, error) {
+	entry := &MaterialManifestEntry{
+		MediaID:    item.MediaID,
+		Type:       mediaType,
+		Name:       item.Name,
+		UpdateTime: item.UpdateTime,
+	}
+
+	if mediaType == MediaTypeNews {
+		entry.File = filepath.Join(string(mediaType), item.MediaID+".json")
+		path, e := mirrorPath(dir, entry.File)
+		if e != nil {
+			return nil, e
+		}
+		return entry, writeFileAtomic(path, func(w io.Writer) error {
+			return jsoniter.NewEncoder(w).Encode(item.Content)
+		})
+	}
+
+	entry.File = filepath.Join(string(mediaType), item.MediaID+filepath.Ext(item.Name))
+	path, e := mirrorPath(dir, entry.File)
+	if e != nil {
+		return nil, e
+	}
+	var content *model.MaterialContent
+	e = writeFileAtomic(path, func(w io.Writer) (e error) {
+		if content, e = obj.MaterialGetTo(item.MediaID, w); e != nil {
+			return e
+		}
+		if content == nil {
+			return nil
+		}
+		if content.DownURL == "" {
+			return errors.New("material " + item.MediaID + " has no binary content")
+		}
+		return obj.download(content.DownURL, w)
+	})
+	if e != nil {
+		return nil, e
+	}
+	if content != nil {
+		entry.Title = content.Title
+		entry.Description = content.Description
+	}
+	return entry, nil
+}
+
+// download 下载视频素材的down_url
+func (obj *OfficialAccount) download(url string, w io.Writer) error {
+	client, e := obj.Client().HTTPClient()
+	if e != nil {
+		return e
+	}
+	resp, e := client.Get(url)
+	if e != nil {
+		return e
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return errors.New("error with code " + resp.Status)
+	}
+	_, e = io.Copy(w, resp.Body)
+	return e
+}
+
+func loadMaterialManifest(dir string) (*MaterialManifest, error) {
+	manifest := &MaterialManifest{Items: make(map[string]*MaterialManifestEntry)}
+	b, e := os.ReadFile(filepath.Join(dir, MaterialMirrorManifest))
+	if errors.Is(e, os.ErrNotExist) {
+		return manifest, nil
+	}
+	if e != nil {
+		return nil, e
+	}
+	if e = jsoniter.Unmarshal(b, manifest); e != nil {
+		return nil, e
+	}
+	if manifest.Items == nil {
+		manifest.Items = make(map[string]*MaterialManifestEntry)
+	}
+	//清单文件可能被篡改，丢弃File离开镜像目录的条目，对应素材将重新下载
+	for id, entry := range manifest.Items {
+		if entry == nil || !filepath.IsLocal(entry.File) {
+			delete(manifest.Items, id)
+		}
+	}
+	return manifest, nil
+}
+
+// mirrorPath 返回镜像目录下的文件路径，拒绝绝对路径及包含../等离开镜像目录的路径
+func mirrorPath(dir, file string) (string, error) {
+	if !filepath.IsLocal(file) {
+		return "", fmt.Errorf("material mirror: %q is outside of %s", file, dir)
+	}
+	return filepath.Join(dir, file), nil
+}
+
+// writeFileAtomic 先写入同目录下的临时文件，成功后再重命名，避免中断时留下不完整的文件
+func writeFileAtomic(path string, write func(w io.Writer) error) error {
+	if e := os.MkdirAll(filepath.Dir(path), os.ModePerm); e != nil {
+		return e
+	}
+	file, e := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if e != nil {
+		return e
+	}
+	defer os.Remove(file.Name())
+	if e = write(file); e != nil {
+		file.Close()
+		return e
+	}
+	if e = file.Close(); e != nil {
+		return e
+	}
+	return os.Rename(file.Name(), path)
+}
@@ -0,0 +1,169 @@
+package webox
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+
+	"webox/api"
+	"webox/model"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// testMaterials 模拟永久素材列表及获取素材接口
+type testMaterials struct {
+	mu      sync.Mutex
+	items   map[MediaType][]*model.MaterialItem
+	offsets []int    // 素材列表请求的offset
+	gets    []string // 获取素材请求的media_id
+}
+
+func (m *testMaterials) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Type    MediaType `json:"type"`
+		Offset  int       `json:"offset"`
+		Count   int       `json:"count"`
+		MediaID string    `json:"media_id"`
+	}
+	body, _ := io.ReadAll(r.Body)
+	_ = jsoniter.Unmarshal(body, &req)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	switch r.URL.Path {
+	case api.ListMaterial:
+		m.offsets = append(m.offsets, req.Offset)
+		items := m.items[req.Type]
+		page := items[min(req.Offset, len(items)):min(req.Offset+req.Count, len(items))]
+		w.Header().Set("Content-Type", "application/json")
+		_ = jsoniter.NewEncoder(w).Encode(&model.MaterialList{TotalCount: len(items), ItemCount: len(page), Item: page})
+	case api.GetMaterial:
+		m.gets = append(m.gets, req.MediaID)
+		w.Header().Set("Content-Type", "image/jpeg")
+		_, _ = w.Write([]byte("image-" + req.MediaID))
+	}
+}
+
+func (m *testMaterials) requests() (offsets []int, gets []string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	offsets, gets = m.offsets, m.gets
+	m.offsets, m.gets = nil, nil
+	return offsets, gets
+}
+
+func newTestMaterials(images int) *testMaterials {
+	m := &testMaterials{items: map[MediaType][]*model.MaterialItem{
+		MediaTypeNews: {{MediaID: "news1", UpdateTime: 1, Content: &model.DraftNews{NewsItem: []*model.DraftArticle{{Title: "图文"}}}}},
+	}}
+	for i := range images {
+		m.items[MediaTypeImage] = append(m.items[MediaTypeImage], &model.MaterialItem{MediaID: fmt.Sprintf("img%02d", i), Name: "a.jpg", UpdateTime: 1})
+	}
+	return m
+}
+
+// TestOfficialAccount_Materials 按offset翻页遍历全部素材
+func TestOfficialAccount_Materials(t *testing.T) {
+	materials := newTestMaterials(MaterialBatchGetMaxCount + 5)
+	srv := httptest.NewServer(materials)
+	defer srv.Close()
+
+	var ids []string
+	for item, e := range testOfficialAccountAt(srv.URL).Materials(MediaTypeImage) {
+		if e != nil {
+			t.Fatal(e)
+		}
+		ids = append(ids, item.MediaID)
+	}
+	if len(ids) != MaterialBatchGetMaxCount+5 || ids[0] != "img00" || ids[len(ids)-1] != "img24" {
+		t.Fatalf("unexpected materials %v", ids)
+	}
+	if offsets, _ := materials.requests(); !slices.Equal(offsets, []int{0, MaterialBatchGetMaxCount}) {
+		t.Fatalf("unexpected offsets %v", offsets)
+	}
+}
+
+// TestOfficialAccount_MaterialMirror 增量镜像：跳过未更新的素材，重新下载更新过的素材并删除已不存在的素材
+func TestOfficialAccount_MaterialMirror(t *testing.T) {
+	materials := newTestMaterials(3)
+	srv := httptest.NewServer(materials)
+	defer srv.Close()
+	oa := testOfficialAccountAt(srv.URL)
+	dir := t.TempDir()
+
+	manifest, e := oa.MaterialMirror(dir)
+	if e != nil {
+		t.Fatal(e)
+	}
+	if _, gets := materials.requests(); len(gets) != 3 || len(manifest.Items) != 4 {
+		t.Fatalf("got %d downloads and %d entries, want 3 and 4", len(gets), len(manifest.Items))
+	}
+	if b, _ := os.ReadFile(filepath.Join(dir, manifest.Items["img01"].File)); string(b) != "image-img01" {
+		t.Fatalf("unexpected image content %q", b)
+	}
+	if b, _ := os.ReadFile(filepath.Join(dir, manifest.Items["news1"].File)); !jsonEqual(string(b), `{"news_item":[{"title":"图文","thumb_media_id":"","content":"","content_source_url":""}]}`) {
+		t.Fatalf("unexpected news content %s", b)
+	}
+
+	materials.mu.Lock()
+	materials.items[MediaTypeImage][1].UpdateTime = 2
+	removed := materials.items[MediaTypeImage][2]
+	materials.items[MediaTypeImage] = materials.items[MediaTypeImage][:2]
+	materials.mu.Unlock()
+
+	if manifest, e = oa.MaterialMirror(dir); e != nil {
+		t.Fatal(e)
+	}
+	if _, gets := materials.requests(); !slices.Equal(gets, []string{"img01"}) {
+		t.Fatalf("got downloads %v, want only the updated img01", gets)
+	}
+	if _, ok := manifest.Items[removed.MediaID]; ok || manifest.Items["img01"].UpdateTime != 2 {
+		t.Fatalf("unexpected manifest %v", manifest.Items)
+	}
+	if _, e = os.Stat(filepath.Join(dir, string(MediaTypeImage), removed.MediaID+".jpg")); !os.IsNotExist(e) {
+		t.Fatalf("stale material file should be removed, got %v", e)
+	}
+
+	loaded, e := loadMaterialManifest(dir)
+	if e != nil || len(loaded.Items) != 3 {
+		t.Fatalf("unexpected saved manifest %v %v", loaded, e)
+	}
+}
+
+// TestOfficialAccount_MaterialMirror_Traversal 清单中离开镜像目录的文件不会被删除
+func TestOfficialAccount_MaterialMirror_Traversal(t *testing.T) {
+	srv := httptest.NewServer(newTestMaterials(0))
+	defer srv.Close()
+
+	root := t.TempDir()
+	dir := filepath.Join(root, "mirror")
+	victim := filepath.Join(root, "victim")
+	if e := os.WriteFile(victim, []byte("keep"), 0o600); e != nil {
+		t.Fatal(e)
+	}
+	if e := os.MkdirAll(dir, 0o700); e != nil {
+		t.Fatal(e)
+	}
+	manifest := `{"items":{"gone":{"media_id":"gone","type":"image","update_time":1,"file":"../victim"},
+"abs":{"media_id":"abs","type":"image","update_time":1,"file":"` + filepath.ToSlash(victim) + `"}}}`
+	if e := os.WriteFile(filepath.Join(dir, MaterialMirrorManifest), []byte(manifest), 0o600); e != nil {
+		t.Fatal(e)
+	}
+
+	if _, e := testOfficialAccountAt(srv.URL).MaterialMirror(dir, MediaTypeImage); e != nil {
+		t.Fatal(e)
+	}
+	if b, e := os.ReadFile(victim); e != nil || string(b) != "keep" {
+		t.Fatalf("file outside the mirror was touched: %q %v", b, e)
+	}
+	if _, e := mirrorPath(dir, "../victim"); e == nil {
+		t.Fatal("expected error for path outside the mirror")
+	}
+}
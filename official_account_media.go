@@ -0,0 +1,167 @@
+package webox
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"webox/api"
+	"webox/util"
+)
+
+// MediaLimit 临时素材的大小及格式限制
+type MediaLimit struct {
+	MaxSize    int64
+	Extensions []string
+}
+
+// MediaLimits 临时素材各类型的限制
+var MediaLimits = map[MediaType]MediaLimit{
+	MediaTypeImage: {10 << 20, []string{".png", ".jpeg", ".jpg", ".gif"}}, // 图片（image）: 10M，支持PNG\JPEG\JPG\GIF格式
+	MediaTypeVoice: {2 << 20, []string{".amr", ".mp3"}},                   // 语音（voice）：2M，播放长度不超过60s，支持AMR\MP3格式
+	MediaTypeVideo: {10 << 20, []string{".mp4"}},                          // 视频（video）：10MB，支持MP4格式
+	MediaTypeThumb: {64 << 10, []string{".jpg", ".jpeg"}},                 // 缩略图（thumb）：64KB，支持JPG格式
+}
+
+// MediaUploadResult ...
+type MediaUploadResult struct {
+	Type         MediaType `json:"type"`
+	MediaID      string    `json:"media_id"`
+	ThumbMediaID string    `json:"thumb_media_id,omitempty"` // 缩略图上传时返回thumb_media_id
+	CreatedAt    int64     `json:"created_at"`
+}
+
+// ValidateMedia 校验临时素材的格式与大小，size未知时传-1
+func ValidateMedia(mediaType MediaType, name string, size int64) error {
+	limit, ok := MediaLimits[mediaType]
+	if !ok {
+		return fmt.Errorf("media: unsupported type %s", mediaType)
+	}
+	if ext := strings.ToLower(filepath.Ext(name)); !slices.Contains(limit.Extensions, ext) {
+		return fmt.Errorf("media: %s must be one of %v, got %q", mediaType, limit.Extensions, ext)
+	}
+	if size > limit.MaxSize {
+		return fmt.Errorf("media: %s must be at most %d bytes, got %d", mediaType, limit.MaxSize, size)
+	}
+	return nil
+}
+
+// mediaSizeReader 读取超过limit字节时返回错误，用于大小未知的素材，使请求在发送完之前中止
+type mediaSizeReader struct {
+	io.Reader
+	limit     int64
+	read      int64
+	mediaType MediaType
+	mu        sync.Mutex
+	err       error
+}
+
+// Read ...
+func (obj *mediaSizeReader) Read(p []byte) (int, error) {
+	n, e := obj.Reader.Read(p)
+	obj.read += int64(n)
+	if obj.read > obj.limit {
+		e = fmt.Errorf("media: %s must be at most %d bytes", obj.mediaType, obj.limit)
+		obj.mu.Lock()
+		obj.err = e
+		obj.mu.Unlock()
+	}
+	return n, e
+}
+
+// Err 返回超出大小限制的错误，请求体由http.Transport在其他goroutine中读取
+func (obj *mediaSizeReader) Err() error {
+	obj.mu.Lock()
+	defer obj.mu.Unlock()
+	return obj.err
+}
+
+// MediaUploadReader 从io.Reader上传临时素材，内容以流的方式发送
+// name为文件名，用于确定格式；size未知时传-1，此时读取超过大小限制即中止上传并返回错误
+// http请求方式：POST/FORM，使用https
+// https://api.weixin.qq.com/cgi-bin/media/upload?access_token=ACCESS_TOKEN&type=TYPE
+func (obj *OfficialAccount) MediaUploadReader(mediaType MediaType, name string, r io.Reader, size int64) (result *MediaUploadResult, e error) {
+
+	if e = ValidateMedia(mediaType, name, size); e != nil {
+		return nil, e
+	}
+	var limited *mediaSizeReader
+	if size >= 0 {
+		r = io.LimitReader(r, size)
+	} else {
+		limit := MediaLimits[mediaType].MaxSize
+		limited = &mediaSizeReader{Reader: io.LimitReader(r, limit+1), limit: limit, mediaType: mediaType}
+		r = limited
+	}
+	u := util.URL(obj.RemoteURL(), api.UploadMedia)
+	resp := obj.Client().Upload(context.Background(), u, util.Map{"type": mediaType.String()}, util.Map{
+		"media": &MultipartFile{Name: name, Reader: r, Size: size},
+	})
+	if limited != nil && limited.Err() != nil {
+		return nil, limited.Err()
+	}
+	if e = resp.Error(); e != nil {
+		return nil, e
+	}
+	result = new(MediaUploadResult)
+	if e = resp.Unmarshal(result); e != nil {
+		return nil, e
+	}
+	return result, nil
+}
+
+// MediaGetTo 获取临时素材并写入w，返回文件名
+// 视频素材返回的是下载地址，会继续下载视频内容写入w
+// http请求方式: GET,https调用
+// https://api.weixin.qq.com/cgi-bin/media/get?access_token=ACCESS_TOKEN&media_id=MEDIA_ID
+func (obj *OfficialAccount) MediaGetTo(mediaID string, w io.Writer) (filename string, e error) {
+	return obj.mediaGetTo(api.GetMedia, mediaID, "", w)
+}
+
+// MediaGetJSSDKTo 获取从JSSDK的uploadVoice接口上传的高清语音素材并写入w，返回文件名
+// 高清语音素材为speex格式（16K采样率），文件名为 MEDIA_ID.speex
+// http请求方式: GET,https调用
+// https://api.weixin.qq.com/cgi-bin/media/get/jssdk?access_token=ACCESS_TOKEN&media_id=MEDIA_ID
+func (obj *OfficialAccount) MediaGetJSSDKTo(mediaID string, w io.Writer) (filename string, e error) {
+	return obj.mediaGetTo(api.GetMediaJssdk, mediaID, ".speex", w)
+}
+
+func (obj *OfficialAccount) mediaGetTo(uri, mediaID, ext string, w io.Writer) (filename string, e error) {
+
+	u := util.URL(obj.RemoteURL(), uri)
+	resp, e := obj.Client().Stream(context.Background(), api.GET, u, util.Map{"media_id": mediaID}, nil)
+	if e != nil {
+		return "", e
+	}
+	if _, params, err := mime.ParseMediaType(resp.Header.Get("Content-Disposition")); err == nil && params["filename"] != "" {
+		filename = filepath.Base(params["filename"])
+	} else if ext != "" {
+		filename = mediaID + ext
+	} else if exts, _ := mime.ExtensionsByType(resp.Header.Get("Content-Type")); len(exts) > 0 {
+		filename = mediaID + exts[0]
+	} else {
+		filename = mediaID
+	}
+
+	r, e := StreamTo(resp, w)
+	if e != nil || r == nil {
+		return filename, e
+	}
+	if e = r.Error(); e != nil {
+		return "", e
+	}
+	var result struct {
+		VideoURL string `json:"video_url"`
+	}
+	if e = r.Unmarshal(&result); e != nil {
+		return "", e
+	}
+	if result.VideoURL == "" {
+		return "", fmt.Errorf("media: unexpected response %s", r.Bytes())
+	}
+	return mediaID + ".mp4", obj.download(result.VideoURL, w)
+}
@@ -0,0 +1,50 @@
+package webox
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// TestOfficialAccount_MediaUploadReader 大小未知的素材读取超过限制时中止上传
+func TestOfficialAccount_MediaUploadReader(t *testing.T) {
+	var received atomic.Int64
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		file, _, e := r.FormFile("media")
+		if e != nil {
+			http.Error(w, e.Error(), http.StatusBadRequest)
+			return
+		}
+		n, _ := io.Copy(io.Discard, file)
+		received.Store(n)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"type":"thumb","thumb_media_id":"thumb1","created_at":1}`))
+	}))
+	defer srv.Close()
+	oa := testOfficialAccountAt(srv.URL)
+	limit := MediaLimits[MediaTypeThumb].MaxSize
+
+	// io.MultiReader隐藏了内容长度，模拟大小未知的流
+	result, e := oa.MediaUploadReader(MediaTypeThumb, "a.jpg", io.MultiReader(bytes.NewReader(make([]byte, limit))), -1)
+	if e != nil || result.ThumbMediaID != "thumb1" || received.Load() != limit {
+		t.Fatalf("got %+v %v with %d bytes, want %d bytes", result, e, received.Load(), limit)
+	}
+
+	received.Store(0)
+	source := strings.NewReader(strings.Repeat("x", int(limit)*2))
+	result, e = oa.MediaUploadReader(MediaTypeThumb, "a.jpg", io.MultiReader(source), -1)
+	if e == nil || !strings.Contains(e.Error(), "at most") || result != nil || received.Load() != 0 {
+		t.Fatalf("got %+v %v with %d bytes received, want size error", result, e, received.Load())
+	}
+	if read := int64(source.Size()) - int64(source.Len()); read > limit+1 {
+		t.Fatalf("read %d bytes from source, want at most %d", read, limit+1)
+	}
+
+	if _, e = oa.MediaUploadReader(MediaTypeThumb, "a.jpg", strings.NewReader("x"), limit+1); e == nil {
+		t.Fatal("expected error for known size over the limit")
+	}
+}
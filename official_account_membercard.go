@@ -0,0 +1,78 @@
+package webox
+
+import (
+	"errors"
+	"webox/api"
+	"webox/model"
+	"webox/util"
+)
+
+// MemberCardActivate 接口激活会员卡
+// HTTP请求方式: POST
+// https://api.weixin.qq.com/card/membercard/activate?access_token=TOKEN
+func (obj *OfficialAccount) MemberCardActivate(activate *model.MemberCardActivate) error {
+	if activate.MembershipNumber == "" || activate.Code == "" {
+		return errors.New("membercard: membership_number and code are required")
+	}
+	return obj.cardPost(api.MemberCardActivate, activate, nil)
+}
+
+// MemberCardSetActivateUserForm 设置一键激活的开卡字段，创建会员卡时须设置wx_activate为true
+// HTTP请求方式: POST
+// https://api.weixin.qq.com/card/membercard/activateuserform/set?access_token=TOKEN
+func (obj *OfficialAccount) MemberCardSetActivateUserForm(form *model.MemberCardActivateUserForm) error {
+	if form.CardID == "" {
+		return errors.New("membercard: card_id is required")
+	}
+	return obj.cardPost(api.MemberCardActivateUserFormSet, form, nil)
+}
+
+// MemberCardActivateURL 获取一键激活的开卡组件链接，outerStr为渠道值，会在事件推送中返回
+// HTTP请求方式: POST
+// https://api.weixin.qq.com/card/membercard/activate/geturl?access_token=TOKEN
+func (obj *OfficialAccount) MemberCardActivateURL(cardID, outerStr string) (url string, e error) {
+	var result struct {
+		URL string `json:"url"`
+	}
+	e = obj.cardPost(api.MemberCardActivateGetURL, util.Map{"card_id": cardID, "outer_str": outerStr}, &result)
+	return result.URL, e
+}
+
+// MemberCardActivateTempInfo 获取用户在一键激活页面提交的开卡信息
+// activateTicket为用户点击激活后跳转的激活页面URL中的activate_ticket参数
+// HTTP请求方式: POST
+// https://api.weixin.qq.com/card/membercard/activatetempinfo/get?access_token=TOKEN
+func (obj *OfficialAccount) MemberCardActivateTempInfo(activateTicket string) (info *model.MemberCardFormInfo, e error) {
+	var result struct {
+		Info *model.MemberCardFormInfo `json:"info"`
+	}
+	if e = obj.cardPost(api.MemberCardActivateTempInfoGet, util.Map{"activate_ticket": activateTicket}, &result); e != nil {
+		return nil, e
+	}
+	return result.Info, nil
+}
+
+// MemberCardUserInfo 拉取会员信息（积分查询）
+// HTTP请求方式: POST
+// https://api.weixin.qq.com/card/membercard/userinfo/get?access_token=TOKEN
+func (obj *OfficialAccount) MemberCardUserInfo(cardID, code string) (info *model.MemberCardUserInfo, e error) {
+	info = new(model.MemberCardUserInfo)
+	if e = obj.cardPost(api.MemberCardUserInfoGet, util.Map{"card_id": cardID, "code": code}, info); e != nil {
+		return nil, e
+	}
+	return info, nil
+}
+
+// MemberCardUpdateUser 更新会员信息，包括积分、余额及自定义会员信息类目
+// HTTP请求方式: POST
+// https://api.weixin.qq.com/card/membercard/updateuser?access_token=TOKEN
+func (obj *OfficialAccount) MemberCardUpdateUser(update *model.MemberCardUpdateUser) (result *model.MemberCardUpdateResult, e error) {
+	if update.CardID == "" || update.Code == "" {
+		return nil, errors.New("membercard: card_id and code are required")
+	}
+	result = new(model.MemberCardUpdateResult)
+	if e = obj.cardPost(api.MemberCardUpdateUser, update, result); e != nil {
+		return nil, e
+	}
+	return result, nil
+}
@@ -0,0 +1,41 @@
+package webox
+
+import (
+	"encoding/xml"
+	"testing"
+	"webox/model"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// TestMemberCardUpdateUser ...
+func TestMemberCardUpdateUser(t *testing.T) {
+	update := model.NewMemberCardUpdateUser("card", "12345").SetBonus(0, "清零").ChangeBalance(-100, "消费")
+	s, _ := jsoniter.MarshalToString(update)
+	want := `{"code":"12345","card_id":"card","bonus":0,"record_bonus":"清零","add_balance":-100,"record_balance":"消费"}`
+	if s != want {
+		t.Fatalf("got %s, want %s", s, want)
+	}
+}
+
+// TestMemberCardUpdateEvent ...
+func TestMemberCardUpdateEvent(t *testing.T) {
+	body := `<xml><ToUserName><![CDATA[gh_9e1765b5568e]]></ToUserName>
+<FromUserName><![CDATA[ojZ8YtyVyr30HheH3CM73y7h4jJE]]></FromUserName>
+<CreateTime>1445507140</CreateTime>
+<MsgType><![CDATA[event]]></MsgType>
+<Event><![CDATA[update_member_card]]></Event>
+<CardId><![CDATA[pjZ8Ytx-nwvpCRyQneH3Ncmh6N94]]></CardId>
+<UserCardCode><![CDATA[485027611252]]></UserCardCode>
+<ModifyBonus>3</ModifyBonus>
+<ModifyBalance>-200</ModifyBalance>
+</xml>`
+	var event model.MemberCardUpdateEvent
+	if err := xml.Unmarshal([]byte(body), &event); err != nil {
+		t.Fatal(err)
+	}
+	if event.Event != model.EventTypeUpdateMemberCard || event.CardID != "pjZ8Ytx-nwvpCRyQneH3Ncmh6N94" ||
+		event.UserCardCode != "485027611252" || event.ModifyBonus != 3 || event.ModifyBalance != -200 {
+		t.Fatalf("unexpected event %+v", event)
+	}
+}
@@ -0,0 +1,453 @@
+package webox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"webox/api"
+	"webox/util"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// MenuButtonType 菜单的响应动作类型
+type MenuButtonType string
+
+// MenuButtonTypeClick ...
+const (
+	MenuButtonTypeClick              MenuButtonType = "click"                // 点击推事件
+	MenuButtonTypeView               MenuButtonType = "view"                 // 跳转URL
+	MenuButtonTypeScancodePush       MenuButtonType = "scancode_push"        // 扫码推事件
+	MenuButtonTypeScancodeWaitmsg    MenuButtonType = "scancode_waitmsg"     // 扫码推事件且弹出“消息接收中”提示框
+	MenuButtonTypePicSysphoto        MenuButtonType = "pic_sysphoto"         // 弹出系统拍照发图
+	MenuButtonTypePicPhotoOrAlbum    MenuButtonType = "pic_photo_or_album"   // 弹出拍照或者相册发图
+	MenuButtonTypePicWeixin          MenuButtonType = "pic_weixin"           // 弹出微信相册发图器
+	MenuButtonTypeLocationSelect     MenuButtonType = "location_select"      // 弹出地理位置选择器
+	MenuButtonTypeMediaID            MenuButtonType = "media_id"             // 下发消息（除文本消息）
+	MenuButtonTypeArticleID          MenuButtonType = "article_id"           // 下发已发布的图文消息
+	MenuButtonTypeArticleViewLimited MenuButtonType = "article_view_limited" // 跳转已发布的图文消息URL
+	MenuButtonTypeMiniProgram        MenuButtonType = "miniprogram"          // 跳转小程序
+)
+
+// 菜单限制
+const (
+	MenuMaxButton        = 3    // 一级菜单数组，个数应为1~3个
+	MenuMaxSubButton     = 5    // 二级菜单数组，个数应为1~5个
+	MenuMaxNameBytes     = 16   // 一级菜单标题，不超过16个字节
+	MenuMaxSubNameBytes  = 60   // 二级菜单标题，不超过60个字节
+	MenuMaxKeyBytes      = 128  // 菜单KEY值，不能超过128字节
+	MenuMaxURLBytes      = 1024 // 网页链接，不能超过1024字节
+	menuErrCodeNotExists = 46003
+)
+
+// MenuButton 菜单按钮定义
+type MenuButton struct {
+	Type      MenuButtonType `json:"type,omitempty" yaml:"type,omitempty"`
+	Name      string         `json:"name" yaml:"name"`
+	Key       string         `json:"key,omitempty" yaml:"key,omitempty"`
+	URL       string         `json:"url,omitempty" yaml:"url,omitempty"`
+	MediaID   string         `json:"media_id,omitempty" yaml:"media_id,omitempty"`
+	ArticleID string         `json:"article_id,omitempty" yaml:"article_id,omitempty"`
+	AppID     string         `json:"appid,omitempty" yaml:"appid,omitempty"`
+	PagePath  string         `json:"pagepath,omitempty" yaml:"pagepath,omitempty"`
+	SubButton []*MenuButton  `json:"sub_button,omitempty" yaml:"sub_button,omitempty"`
+}
+
+// Menu 菜单，MatchRule不为空时为个性化菜单
+type Menu struct {
+	Button    []*MenuButton `json:"button" yaml:"button"`
+	MatchRule *MatchRule    `json:"matchrule,omitempty" yaml:"matchrule,omitempty"`
+	MenuID    int64         `json:"menuid,omitempty" yaml:"-"`
+}
+
+// MenuDefinition 菜单定义，与查询菜单接口返回的结构一致
+// 本包只内置JSON解析，不依赖yaml库；结构体带有yaml标签，YAML定义通过ParseMenuDefinitionWith传入yaml库的Unmarshal解析
+type MenuDefinition struct {
+	Menu            *Menu   `json:"menu" yaml:"menu"`
+	ConditionalMenu []*Menu `json:"conditionalmenu,omitempty" yaml:"conditionalmenu,omitempty"`
+}
+
+// ParseMenuDefinition 解析JSON格式的菜单定义
+func ParseMenuDefinition(data []byte) (*MenuDefinition, error) {
+	def := new(MenuDefinition)
+	if e := jsoniter.Unmarshal(data, def); e != nil {
+		return nil, e
+	}
+	return def, nil
+}
+
+// ParseMenuDefinitionWith 使用unmarshal解析菜单定义，用于YAML等其他格式，
+// 如 ParseMenuDefinitionWith(data, yaml.Unmarshal)，unmarshal须按yaml标签解析到结构体
+func ParseMenuDefinitionWith(data []byte, unmarshal func(data []byte, v any) error) (*MenuDefinition, error) {
+	def := new(MenuDefinition)
+	if e := unmarshal(data, def); e != nil {
+		return nil, e
+	}
+	return def, nil
+}
+
+// MenuError 菜单校验错误，Path指向出错的字段，如 conditionalmenu[0].button[1].sub_button[2].url
+type MenuError struct {
+	Path string
+	Msg  string
+}
+
+// Error ...
+func (e *MenuError) Error() string {
+	return e.Path + ": " + e.Msg
+}
+
+// MenuErrors ...
+type MenuErrors []*MenuError
+
+// Error ...
+func (e MenuErrors) Error() string {
+	var s []string
+	for _, v := range e {
+		s = append(s, v.Error())
+	}
+	return strings.Join(s, "; ")
+}
+
+// Validate 校验菜单定义，返回全部不符合微信限制的字段
+func (obj *MenuDefinition) Validate() error {
+	var errs MenuErrors
+	if obj.Menu == nil {
+		errs = append(errs, &MenuError{Path: "menu", Msg: "is required"})
+	} else {
+		errs = obj.Menu.validate("menu", errs)
+	}
+	for i, menu := range obj.ConditionalMenu {
+		path := fmt.Sprintf("conditionalmenu[%d]", i)
+		if menu.MatchRule == nil {
+			errs = append(errs, &MenuError{Path: path + ".matchrule", Msg: "is required"})
+		} else if err := menu.MatchRule.Validate(); err != nil {
+			errs = append(errs, &MenuError{Path: path + "." + err.Path, Msg: err.Msg})
+		}
+		errs = menu.validate(path, errs)
+	}
+	if errs != nil {
+		return errs
+	}
+	return nil
+}
+
+// Validate 校验菜单
+func (obj *Menu) Validate() error {
+	if errs := obj.validate("menu", nil); errs != nil {
+		return errs
+	}
+	return nil
+}
+
+func (obj *Menu) validate(path string, errs MenuErrors) MenuErrors {
+	if n := len(obj.Button); n == 0 || n > MenuMaxButton {
+		errs = append(errs, &MenuError{Path: path + ".button", Msg: fmt.Sprintf("must have 1 to %d buttons, got %d", MenuMaxButton, n)})
+	}
+	for i, button := range obj.Button {
+		p := fmt.Sprintf("%s.button[%d]", path, i)
+		errs = button.validate(p, MenuMaxNameBytes, errs)
+		if len(button.SubButton) == 0 {
+			continue
+		}
+		if n := len(button.SubButton); n > MenuMaxSubButton {
+			errs = append(errs, &MenuError{Path: p + ".sub_button", Msg: fmt.Sprintf("must have at most %d buttons, got %d", MenuMaxSubButton, n)})
+		}
+		if button.Type != "" {
+			errs = append(errs, &MenuError{Path: p + ".type", Msg: "must be empty when sub_button is set"})
+		}
+		for j, sub := range button.SubButton {
+			sp := fmt.Sprintf("%s.sub_button[%d]", p, j)
+			errs = sub.validate(sp, MenuMaxSubNameBytes, errs)
+			if len(sub.SubButton) > 0 {
+				errs = append(errs, &MenuError{Path: sp + ".sub_button", Msg: "only two levels of menu are supported"})
+			}
+		}
+	}
+	return errs
+}
+
+func (obj *MenuButton) validate(path string, maxName int, errs MenuErrors) MenuErrors {
+	required := func(field, v string) {
+		if v == "" {
+			errs = append(errs, &MenuError{Path: path + "." + field, Msg: fmt.Sprintf("is required for type %s", obj.Type)})
+		}
+	}
+
+	if obj.Name == "" {
+		errs = append(errs, &MenuError{Path: path + ".name", Msg: "is required"})
+	} else if len(obj.Name) > maxName {
+		errs = append(errs, &MenuError{Path: path + ".name", Msg: fmt.Sprintf("must be at most %d bytes, got %d", maxName, len(obj.Name))})
+	}
+	if len(obj.Key) > MenuMaxKeyBytes {
+		errs = append(errs, &MenuError{Path: path + ".key", Msg: fmt.Sprintf("must be at most %d bytes, got %d", MenuMaxKeyBytes, len(obj.Key))})
+	}
+	if len(obj.URL) > MenuMaxURLBytes {
+		errs = append(errs, &MenuError{Path: path + ".url", Msg: fmt.Sprintf("must be at most %d bytes, got %d", MenuMaxURLBytes, len(obj.URL))})
+	}
+
+	switch obj.Type {
+	case "":
+		if len(obj.SubButton) == 0 {
+			errs = append(errs, &MenuError{Path: path + ".type", Msg: "is required when sub_button is empty"})
+		}
+	case MenuButtonTypeClick, MenuButtonTypeScancodePush, MenuButtonTypeScancodeWaitmsg, MenuButtonTypePicSysphoto,
+		MenuButtonTypePicPhotoOrAlbum, MenuButtonTypePicWeixin, MenuButtonTypeLocationSelect:
+		required("key", obj.Key)
+	case MenuButtonTypeView:
+		required("url", obj.URL)
+	case MenuButtonTypeMediaID:
+		required("media_id", obj.MediaID)
+	case MenuButtonTypeArticleID, MenuButtonTypeArticleViewLimited:
+		required("article_id", obj.ArticleID)
+	case MenuButtonTypeMiniProgram:
+		required("url", obj.URL)
+		required("appid", obj.AppID)
+		required("pagepath", obj.PagePath)
+	default:
+		errs = append(errs, &MenuError{Path: path + ".type", Msg: fmt.Sprintf("unknown type %s", obj.Type)})
+	}
+	return errs
+}
+
+// MenuChange 菜单差异，From为空表示新增，To为空表示删除
+type MenuChange struct {
+	Path string
+	From string
+	To   string
+}
+
+// String ...
+func (obj *MenuChange) String() string {
+	return fmt.Sprintf("%s: %q -> %q", obj.Path, obj.From, obj.To)
+}
+
+// Diff 比较当前菜单与菜单定义，返回按字段路径排序的差异
+func (obj *MenuDefinition) Diff(current *MenuDefinition) []*MenuChange {
+	return diffFields(current.fields(), obj.fields())
+}
+
+func (obj *MenuDefinition) fields() map[string]string {
+	fields := make(map[string]string)
+	if obj == nil {
+		return fields
+	}
+	if obj.Menu != nil {
+		obj.Menu.flatten("menu", fields)
+	}
+	for i, menu := range obj.ConditionalMenu {
+		menu.flatten(fmt.Sprintf("conditionalmenu[%d]", i), fields)
+	}
+	return fields
+}
+
+func (obj *Menu) flatten(path string, fields map[string]string) {
+	for i, button := range obj.Button {
+		button.flatten(fmt.Sprintf("%s.button[%d]", path, i), fields)
+	}
+	if obj.MatchRule != nil {
+		r := obj.MatchRule.normalized()
+		p := path + ".matchrule."
+		for k, v := range map[string]string{
+			"tag_id":               r.TagID,
+			"sex":                  r.Sex,
+			"country":              r.Country,
+			"province":             r.Province,
+			"city":                 r.City,
+			"client_platform_type": r.ClientPlatformType,
+			"language":             r.Language,
+		} {
+			if v != "" {
+				fields[p+k] = v
+			}
+		}
+	}
+}
+
+func (obj *MenuButton) flatten(path string, fields map[string]string) {
+	for k, v := range map[string]string{
+		"type":       string(obj.Type),
+		"name":       obj.Name,
+		"key":        obj.Key,
+		"url":        obj.URL,
+		"media_id":   obj.MediaID,
+		"article_id": obj.ArticleID,
+		"appid":      obj.AppID,
+		"pagepath":   obj.PagePath,
+	} {
+		if v != "" {
+			fields[path+"."+k] = v
+		}
+	}
+	for i, sub := range obj.SubButton {
+		sub.flatten(fmt.Sprintf("%s.sub_button[%d]", path, i), fields)
+	}
+}
+
+func diffFields(from, to map[string]string) []*MenuChange {
+	var changes []*MenuChange
+	keys := slices.Collect(maps.Keys(from))
+	for k := range to {
+		if _, ok := from[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	slices.Sort(keys)
+	for _, k := range keys {
+		if from[k] != to[k] {
+			changes = append(changes, &MenuChange{Path: k, From: from[k], To: to[k]})
+		}
+	}
+	return changes
+}
+
+// equal 比较按钮及规范化后的匹配规则，忽略menuid
+func (obj *Menu) equal(other *Menu) bool {
+	a, b := make(map[string]string), make(map[string]string)
+	obj.flatten("", a)
+	other.flatten("", b)
+	return maps.Equal(a, b)
+}
+
+// MenuConfig 查询当前通过接口设置的菜单配置（含个性化菜单及其menuid）
+// 未创建菜单时返回空的菜单定义
+// http请求方式:GET
+// https://api.weixin.qq.com/cgi-bin/menu/get?access_token=ACCESS_TOKEN
+func (obj *OfficialAccount) MenuConfig() (def *MenuDefinition, e error) {
+
+	resp := obj.MenuList()
+	if code, _ := resp.ToMap().GetInt64("errcode"); code == menuErrCodeNotExists {
+		return &MenuDefinition{}, nil
+	}
+	if e = resp.Error(); e != nil {
+		return nil, e
+	}
+	def = new(MenuDefinition)
+	if e = resp.Unmarshal(def); e != nil {
+		return nil, e
+	}
+	return def, nil
+}
+
+// MenuDiff 比较当前菜单与菜单定义
+// get_current_selfmenu_info 不返回个性化菜单及menuid，因此以查询菜单接口的结果为准
+func (obj *OfficialAccount) MenuDiff(def *MenuDefinition) ([]*MenuChange, error) {
+	current, e := obj.MenuConfig()
+	if e != nil {
+		return nil, e
+	}
+	return def.Diff(current), nil
+}
+
+// MenuApply 校验并应用菜单定义，只调用必要的接口：
+// 默认菜单有变化时重新创建；删除定义中不存在的个性化菜单，新增当前不存在的个性化菜单
+func (obj *OfficialAccount) MenuApply(def *MenuDefinition) error {
+	if e := def.Validate(); e != nil {
+		return e
+	}
+	current, e := obj.MenuConfig()
+	if e != nil {
+		return e
+	}
+
+	if current.Menu == nil || !current.Menu.equal(def.Menu) {
+		if e = obj.menuPost(api.MenuCreate, &Menu{Button: def.Menu.Button}).Error(); e != nil {
+			return e
+		}
+		// 重新读取，以得到创建默认菜单后仍存在的个性化菜单
+		if current, e = obj.MenuConfig(); e != nil {
+			return e
+		}
+	}
+
+	var keep []*Menu
+	for _, menu := range current.ConditionalMenu {
+		if slices.ContainsFunc(def.ConditionalMenu, menu.equal) {
+			keep = append(keep, menu)
+			continue
+		}
+		if e = obj.MenuDeleteConditional(menu.MenuID).Error(); e != nil {
+			return e
+		}
+	}
+	for _, menu := range def.ConditionalMenu {
+		if slices.ContainsFunc(keep, menu.equal) {
+			continue
+		}
+		if _, e = obj.MenuAddConditional(menu); e != nil {
+			return e
+		}
+	}
+	return nil
+}
+
+func (obj *OfficialAccount) menuPost(uri string, menu *Menu) Responder {
+
+	u := util.URL(obj.RemoteURL(), uri)
+	return obj.Client().Post(context.Background(), u, nil, menu)
+}
+
+// MenuAddConditional 创建个性化菜单，返回menuid
+// http请求方式:POST（请使用https协议）
+// https://api.weixin.qq.com/cgi-bin/menu/addconditional?access_token=ACCESS_TOKEN
+func (obj *OfficialAccount) MenuAddConditional(menu *Menu) (menuID int64, e error) {
+
+	if menu.MatchRule == nil {
+		return 0, &MenuError{Path: "matchrule", Msg: "is required"}
+	}
+	if err := menu.MatchRule.Validate(); err != nil {
+		return 0, err
+	}
+	resp := obj.menuPost(api.AddMenuConditional, &Menu{Button: menu.Button, MatchRule: menu.MatchRule})
+	if e = resp.Error(); e != nil {
+		return 0, e
+	}
+	var result struct {
+		MenuID json.Number `json:"menuid"`
+	}
+	if e = resp.Unmarshal(&result); e != nil {
+		return 0, e
+	}
+	return result.MenuID.Int64()
+}
+
+// MenuConditionalList 查询全部个性化菜单，匹配规则中的数值及group_id由MatchRule.UnmarshalJSON转换
+// http请求方式:GET
+// https://api.weixin.qq.com/cgi-bin/menu/get?access_token=ACCESS_TOKEN
+func (obj *OfficialAccount) MenuConditionalList() ([]*Menu, error) {
+	def, e := obj.MenuConfig()
+	if e != nil {
+		return nil, e
+	}
+	return def.ConditionalMenu, nil
+}
+
+// MenuDeleteConditional 删除个性化菜单
+// http请求方式:POST（请使用https协议）
+// https://api.weixin.qq.com/cgi-bin/menu/delconditional?access_token=ACCESS_TOKEN
+func (obj *OfficialAccount) MenuDeleteConditional(menuID int64) Responder {
+
+	u := util.URL(obj.RemoteURL(), api.DeleteMenuConditional)
+	return obj.Client().Post(context.Background(), u, nil, util.Map{"menuid": strconv.FormatInt(menuID, 10)})
+}
+
+// MenuTryMatchButtons 测试个性化菜单匹配结果
+// userID可以是粉丝的OpenID，也可以是粉丝的微信号
+// http请求方式:POST（请使用https协议）
+// https://api.weixin.qq.com/cgi-bin/menu/trymatch?access_token=ACCESS_TOKEN
+func (obj *OfficialAccount) MenuTryMatchButtons(userID string) (buttons []*MenuButton, e error) {
+
+	resp := obj.MenuTryMatch(userID)
+	if e = resp.Error(); e != nil {
+		return nil, e
+	}
+	var result Menu
+	if e = resp.Unmarshal(&result); e != nil {
+		return nil, e
+	}
+	return result.Button, nil
+}
@@ -0,0 +1,189 @@
+package webox
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"webox/api"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// testMenuGet 查询菜单接口的真实返回，个性化菜单的匹配规则为数值且标签字段为group_id
+const testMenuGet = `{"menu":{"button":[{"type":"click","name":"今日歌曲","key":"V1001_TODAY_MUSIC","sub_button":[]},
+{"name":"菜单","sub_button":[{"type":"view","name":"搜索","url":"http://www.soso.com/","sub_button":[]}]}],"menuid":208396938},
+"conditionalmenu":[{"button":[{"type":"click","name":"今日歌曲","key":"V1001_TODAY_MUSIC","sub_button":[]}],
+"matchrule":{"group_id":2,"sex":1,"country":"中国","province":"广东","city":"广州","client_platform_type":2},"menuid":208396993}]}`
+
+// testMenuDefinition 与testMenuGet相同的菜单定义
+const testMenuDefinition = `{"menu":{"button":[{"type":"click","name":"今日歌曲","key":"V1001_TODAY_MUSIC"},
+{"name":"菜单","sub_button":[{"type":"view","name":"搜索","url":"http://www.soso.com/"}]}]},
+"conditionalmenu":[{"button":[{"type":"click","name":"今日歌曲","key":"V1001_TODAY_MUSIC"}],
+"matchrule":{"tag_id":"2","sex":"1","country":"中国","province":"广东","city":"广州","client_platform_type":"2"}}]}`
+
+// TestMenuDefinition_Validate ...
+func TestMenuDefinition_Validate(t *testing.T) {
+	def, e := ParseMenuDefinition([]byte(`{
+"menu":{"button":[
+	{"type":"click","name":"今日歌曲","key":"V1001_TODAY_MUSIC"},
+	{"name":"菜单","sub_button":[
+		{"type":"view","name":"搜索"},
+		{"type":"miniprogram","name":"wxa","url":"http://mp.weixin.qq.com","appid":"wx286b93c14bbf93aa"}
+	]}
+]},
+"conditionalmenu":[{"button":[{"type":"scancode_push","name":"一个很长很长的菜单名","key":"rselfmenu_0_1"}]}]
+}`))
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	var paths []string
+	for _, err := range def.Validate().(MenuErrors) {
+		paths = append(paths, err.Path)
+	}
+	want := []string{
+		"menu.button[1].sub_button[0].url",
+		"menu.button[1].sub_button[1].pagepath",
+		"conditionalmenu[0].matchrule",
+		"conditionalmenu[0].button[0].name",
+	}
+	if !slices.Equal(paths, want) {
+		t.Fatalf("unexpected error paths %v", paths)
+	}
+}
+
+// TestMenuDefinition_Diff ...
+func TestMenuDefinition_Diff(t *testing.T) {
+	current := &MenuDefinition{Menu: &Menu{Button: []*MenuButton{
+		{Type: MenuButtonTypeClick, Name: "a", Key: "k1"},
+		{Type: MenuButtonTypeView, Name: "b", URL: "http://a"},
+	}}}
+	def := &MenuDefinition{Menu: &Menu{Button: []*MenuButton{
+		{Type: MenuButtonTypeClick, Name: "a", Key: "k2"},
+	}}}
+
+	var changes []string
+	for _, c := range def.Diff(current) {
+		changes = append(changes, c.String())
+	}
+	want := `menu.button[0].key: "k1" -> "k2"|menu.button[1].name: "b" -> ""|menu.button[1].type: "view" -> ""|menu.button[1].url: "http://a" -> ""`
+	if got := strings.Join(changes, "|"); got != want {
+		t.Fatalf("unexpected diff %s", got)
+	}
+}
+
+// TestMatchRule_Validate ...
+func TestMatchRule_Validate(t *testing.T) {
+	for rule, path := range map[MatchRule]string{
+		{}:                              "matchrule",
+		{Sex: "0"}:                      "matchrule.sex",
+		{City: "广州", Province: "广东"}:    "matchrule.country",
+		{ClientPlatformType: "4"}:       "matchrule.client_platform_type",
+		{Language: "zh"}:                "matchrule.language",
+		{TagID: "abc"}:                  "matchrule.tag_id",
+		{Country: "中国", Province: "广东"}: "",
+	} {
+		err := rule.Validate()
+		if (err == nil && path != "") || (err != nil && err.Path != path) {
+			t.Fatalf("unexpected error %v for %+v", err, rule)
+		}
+	}
+}
+
+// TestMatchRule_UnmarshalJSON 数值字段转换为字符串，group_id作为tag_id
+func TestMatchRule_UnmarshalJSON(t *testing.T) {
+	for data, want := range map[string]MatchRule{
+		`{"group_id":2,"sex":1,"client_platform_type":2,"language":"zh_CN"}`: {TagID: "2", Sex: "1", ClientPlatformType: "2", Language: "zh_CN"},
+		`{"tag_id":"100","group_id":2,"sex":"","country":null}`:              {TagID: "100"},
+		`{"group_id":2,"tag_id":""}`:                                         {TagID: "2"},
+	} {
+		var got MatchRule
+		if e := jsoniter.UnmarshalFromString(data, &got); e != nil || got != want {
+			t.Fatalf("%s: got %+v %v, want %+v", data, got, e, want)
+		}
+	}
+	var rule MatchRule
+	if e := jsoniter.UnmarshalFromString(`{"sex":{}}`, &rule); e == nil {
+		t.Fatal("expected error for object value")
+	}
+}
+
+// TestOfficialAccount_MenuConfig 查询菜单接口返回个性化菜单时的解析及个性化菜单列表
+func TestOfficialAccount_MenuConfig(t *testing.T) {
+	oa, _ := newTestOfficialAccount(t, map[string]string{api.GetMenu: testMenuGet})
+
+	def, e := oa.MenuConfig()
+	if e != nil {
+		t.Fatal(e)
+	}
+	if def.Menu.MenuID != 208396938 || len(def.Menu.Button) != 2 || def.Menu.Button[1].SubButton[0].URL != "http://www.soso.com/" {
+		t.Fatalf("unexpected menu %+v", def.Menu)
+	}
+	menus, e := oa.MenuConditionalList()
+	if e != nil {
+		t.Fatal(e)
+	}
+	want := MatchRule{TagID: "2", Sex: "1", Country: "中国", Province: "广东", City: "广州", ClientPlatformType: "2"}
+	if len(menus) != 1 || menus[0].MenuID != 208396993 || *menus[0].MatchRule != want {
+		t.Fatalf("unexpected conditional menus %+v", menus)
+	}
+}
+
+// TestOfficialAccount_MenuApply 定义与当前菜单相同时只查询不修改，个性化菜单变化时只替换该菜单
+func TestOfficialAccount_MenuApply(t *testing.T) {
+	oa, requests := newTestOfficialAccount(t, map[string]string{
+		api.GetMenu:            testMenuGet,
+		api.AddMenuConditional: `{"menuid":"208397000"}`,
+	})
+	def, e := ParseMenuDefinition([]byte(testMenuDefinition))
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	if changes, e := oa.MenuDiff(def); e != nil || len(changes) != 0 {
+		t.Fatalf("unexpected changes %v %v", changes, e)
+	}
+	if e = oa.MenuApply(def); e != nil {
+		t.Fatal(e)
+	}
+	for _, r := range requests() {
+		if r.Path != api.GetMenu {
+			t.Fatalf("unchanged menu should not call %s", r.Path)
+		}
+	}
+
+	def.ConditionalMenu[0].MatchRule.Sex = "2"
+	if e = oa.MenuApply(def); e != nil {
+		t.Fatal(e)
+	}
+	var paths []string
+	for _, r := range requests() {
+		if r.Path != api.GetMenu {
+			paths = append(paths, r.Path+" "+r.Body)
+		}
+	}
+	if len(paths) != 2 || paths[0] != api.DeleteMenuConditional+` {"menuid":"208396993"}` || !strings.HasPrefix(paths[1], api.AddMenuConditional) ||
+		!strings.Contains(paths[1], `"sex":"2"`) {
+		t.Fatalf("unexpected requests %v", paths)
+	}
+}
+
+// TestParseMenuDefinitionWith 其他格式通过传入的unmarshal解析，匹配规则规范化后比较
+func TestParseMenuDefinitionWith(t *testing.T) {
+	def, e := ParseMenuDefinitionWith([]byte("menu"), func(data []byte, v any) error {
+		//模拟yaml库将 tag_id: 002 解析为字符串
+		*v.(*MenuDefinition) = MenuDefinition{
+			Menu:            &Menu{Button: []*MenuButton{{Type: MenuButtonTypeClick, Name: "a", Key: "k"}}},
+			ConditionalMenu: []*Menu{{Button: []*MenuButton{{Type: MenuButtonTypeClick, Name: "a", Key: "k"}}, MatchRule: &MatchRule{TagID: "002", Sex: "1"}}},
+		}
+		return nil
+	})
+	if e != nil || def.Validate() != nil {
+		t.Fatalf("unexpected definition %v %v", def, e)
+	}
+	current := &Menu{Button: def.ConditionalMenu[0].Button, MatchRule: &MatchRule{TagID: "2", Sex: "1"}}
+	if !current.equal(def.ConditionalMenu[0]) {
+		t.Fatal("normalized match rules should be equal")
+	}
+}
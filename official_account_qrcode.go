@@ -0,0 +1,230 @@
+package webox
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"net/url"
+	"time"
+	"unicode/utf8"
+	"webox/api"
+	"webox/model"
+	"webox/qrcode"
+	"webox/util"
+)
+
+// 带参数二维码的限制
+const (
+	QrCodeExpireMax       = 30 * 24 * time.Hour // 临时二维码最长可以设置为在二维码生成后的30天
+	QrCodeExpireDefault   = 30 * time.Second    // 不填expire_seconds时默认有效期为30秒
+	QrCodeLimitSceneIDMax = 100000              // 永久二维码的整型参数值范围为1-100000
+	QrCodeSceneStrMaxLen  = 64                  // 字符串参数值长度限制为1到64
+)
+
+// QrCodeFormat 二维码图片格式
+type QrCodeFormat string
+
+// 二维码图片格式，JPG为通过showqrcode换取的微信二维码图片，PNG和SVG为根据二维码URL在本地生成
+const (
+	QrCodeFormatJPG QrCodeFormat = "jpg"
+	QrCodeFormatPNG QrCodeFormat = "png"
+	QrCodeFormatSVG QrCodeFormat = "svg"
+)
+
+// QrCodeRenderScale 本地生成二维码图片时每个模块的默认像素数
+const QrCodeRenderScale = 8
+
+// QrCodeCreateTemporary 创建整型参数值的临时二维码，expire为0时使用默认有效期30秒
+func (obj *OfficialAccount) QrCodeCreateTemporary(sceneID int, expire time.Duration) (*model.QrCodeTicket, error) {
+	return obj.QrCodeCreateTicket(&model.QrCodeAction{
+		ExpireSeconds: int(expire / time.Second),
+		ActionName:    model.QrScene,
+		ActionInfo:    model.QrCodeActionInfo{Scene: &model.QrCodeScene{SceneID: sceneID}},
+	})
+}
+
+// QrCodeCreateTemporaryStr 创建字符串参数值的临时二维码，expire为0时使用默认有效期30秒
+func (obj *OfficialAccount) QrCodeCreateTemporaryStr(scene string, expire time.Duration) (*model.QrCodeTicket, error) {
+	return obj.QrCodeCreateTicket(&model.QrCodeAction{
+		ExpireSeconds: int(expire / time.Second),
+		ActionName:    model.QrStrScene,
+		ActionInfo:    model.QrCodeActionInfo{Scene: &model.QrCodeScene{SceneStr: scene}},
+	})
+}
+
+// QrCodeCreateLimit 创建整型参数值的永久二维码
+func (obj *OfficialAccount) QrCodeCreateLimit(sceneID int) (*model.QrCodeTicket, error) {
+	return obj.QrCodeCreateTicket(&model.QrCodeAction{
+		ActionName: model.QrLimitScene,
+		ActionInfo: model.QrCodeActionInfo{Scene: &model.QrCodeScene{SceneID: sceneID}},
+	})
+}
+
+// QrCodeCreateLimitStr 创建字符串参数值的永久二维码
+func (obj *OfficialAccount) QrCodeCreateLimitStr(scene string) (*model.QrCodeTicket, error) {
+	return obj.QrCodeCreateTicket(&model.QrCodeAction{
+		ActionName: model.QrLimitStrScene,
+		ActionInfo: model.QrCodeActionInfo{Scene: &model.QrCodeScene{SceneStr: scene}},
+	})
+}
+
+// QrCodeCreateTicket 创建二维码ticket，并根据expire_seconds计算过期时间
+// http请求方式: POST
+// https://api.weixin.qq.com/cgi-bin/qrcode/create?access_token=TOKEN
+func (obj *OfficialAccount) QrCodeCreateTicket(action *model.QrCodeAction) (ticket *model.QrCodeTicket, e error) {
+
+	if e = ValidateQrCodeAction(action); e != nil {
+		return nil, e
+	}
+	now := time.Now()
+	resp := obj.QrCodeCreate(action)
+	if e = resp.Error(); e != nil {
+		return nil, e
+	}
+	ticket = new(model.QrCodeTicket)
+	if e = resp.Unmarshal(ticket); e != nil {
+		return nil, e
+	}
+	if ticket.ExpireSeconds > 0 {
+		ticket.ExpiresAt = now.Add(time.Duration(ticket.ExpireSeconds) * time.Second)
+	}
+	return ticket, nil
+}
+
+// ValidateQrCodeAction 校验带参数二维码的参数值及有效期，卡券二维码不做校验
+func ValidateQrCodeAction(action *model.QrCodeAction) error {
+	if action == nil {
+		return fmt.Errorf("qrcode: nil action")
+	}
+	scene := action.ActionInfo.Scene
+	switch action.ActionName {
+	case model.QrScene, model.QrStrScene:
+		if action.ExpireSeconds < 0 || action.ExpireSeconds > int(QrCodeExpireMax/time.Second) {
+			return fmt.Errorf("qrcode: expire_seconds must be at most %d, got %d", int(QrCodeExpireMax/time.Second), action.ExpireSeconds)
+		}
+	case model.QrLimitScene, model.QrLimitStrScene:
+		if action.ExpireSeconds != 0 {
+			return fmt.Errorf("qrcode: %s does not expire", action.ActionName)
+		}
+	default:
+		return nil
+	}
+	if scene == nil {
+		return fmt.Errorf("qrcode: %s requires a scene", action.ActionName)
+	}
+
+	switch action.ActionName {
+	case model.QrScene:
+		if scene.SceneID <= 0 || int64(scene.SceneID) > math.MaxUint32 {
+			return fmt.Errorf("qrcode: scene_id must be a positive 32-bit integer, got %d", scene.SceneID)
+		}
+	case model.QrLimitScene:
+		if scene.SceneID < 1 || scene.SceneID > QrCodeLimitSceneIDMax {
+			return fmt.Errorf("qrcode: scene_id must be in [1, %d], got %d", QrCodeLimitSceneIDMax, scene.SceneID)
+		}
+	default:
+		if n := utf8.RuneCountInString(scene.SceneStr); n < 1 || n > QrCodeSceneStrMaxLen {
+			return fmt.Errorf("qrcode: scene_str length must be in [1, %d], got %d", QrCodeSceneStrMaxLen, n)
+		}
+	}
+	return nil
+}
+
+// QrCodeShowURL 通过ticket换取二维码图片的地址，ticket需要UrlEncode
+// HTTP GET请求（请使用https协议）https://mp.weixin.qq.com/cgi-bin/showqrcode?ticket=TICKET
+func QrCodeShowURL(ticket string) string {
+	return api.MpWeixin + api.ShowQrcode + "?ticket=" + url.QueryEscape(ticket)
+}
+
+// QrCodeImageTo 将二维码图片写入w
+// format为QrCodeFormatJPG时从showqrcode下载微信生成的图片，为PNG或SVG时根据ticket.URL在本地生成，不产生网络请求
+func (obj *OfficialAccount) QrCodeImageTo(ticket *model.QrCodeTicket, format QrCodeFormat, w io.Writer) error {
+	if format == QrCodeFormatJPG {
+		return obj.download(QrCodeShowURL(ticket.Ticket), w)
+	}
+	return QrCodeRender(w, ticket.URL, format, QrCodeRenderScale)
+}
+
+// QrCodeRender 将content编码为二维码图片写入w，scale为每个模块的像素数
+func QrCodeRender(w io.Writer, content string, format QrCodeFormat, scale int) error {
+	code, e := qrcode.Encode(content, qrcode.LevelM)
+	if e != nil {
+		return e
+	}
+	switch format {
+	case QrCodeFormatPNG:
+		return code.WritePNG(w, scale)
+	case QrCodeFormatSVG:
+		return code.WriteSVG(w, scale)
+	}
+	return fmt.Errorf("qrcode: unsupported format %q", format)
+}
+
+// qrCodeAttributionGrace 临时二维码过期后归因记录的保留时间
+// 用户在二维码过期前扫码，可能在过期后才完成关注
+const qrCodeAttributionGrace = time.Hour
+
+// QrCodeAttribution 带参数二维码的渠道归因
+// 创建二维码时将参数值与活动ID的对应关系保存在缓存中，收到扫码或扫码关注事件时据此找回活动ID
+// 多个进程处理同一公众号的事件时，应通过QrCodeAttributionCache使用共享的缓存
+type QrCodeAttribution struct {
+	cacheScope
+	officialAccount *OfficialAccount
+}
+
+// QrCodeAttribution ...
+func (obj *OfficialAccount) QrCodeAttribution(options ...QrCodeAttributionOption) *QrCodeAttribution {
+	attribution := &QrCodeAttribution{officialAccount: obj, cacheScope: obj.cacheScope}
+	for _, o := range options {
+		o(attribution)
+	}
+	return attribution
+}
+
+// CreateTemporary 为活动创建临时二维码，参数值随机生成
+func (obj *QrCodeAttribution) CreateTemporary(campaignID string, expire time.Duration) (*model.QrCodeTicket, error) {
+	scene := util.GenerateUUID()
+	ticket, e := obj.officialAccount.QrCodeCreateTemporaryStr(scene, expire)
+	if e != nil {
+		return nil, e
+	}
+	if expire == 0 {
+		expire = QrCodeExpireDefault
+	}
+	obj.Bind(scene, campaignID, expire+qrCodeAttributionGrace)
+	return ticket, nil
+}
+
+// CreateLimit 为活动创建永久二维码，归因记录不过期
+func (obj *QrCodeAttribution) CreateLimit(campaignID, scene string) (*model.QrCodeTicket, error) {
+	ticket, e := obj.officialAccount.QrCodeCreateLimitStr(scene)
+	if e != nil {
+		return nil, e
+	}
+	obj.Bind(scene, campaignID, 0)
+	return ticket, nil
+}
+
+// Bind 记录二维码参数值对应的活动ID，ttl为0时不过期
+func (obj *QrCodeAttribution) Bind(scene, campaignID string, ttl time.Duration) {
+	obj.getCache().Set(obj.sceneKey(scene), campaignID, ttl)
+}
+
+// Campaign 返回二维码参数值对应的活动ID
+func (obj *QrCodeAttribution) Campaign(scene string) (string, bool) {
+	campaignID, ok := obj.getCache().Get(obj.sceneKey(scene)).(string)
+	return campaignID, ok
+}
+
+// Resolve 返回扫码或扫码关注事件对应的活动ID，非带参数二维码事件或参数值未记录时返回false
+func (obj *QrCodeAttribution) Resolve(event *model.QrCodeScanEvent) (string, bool) {
+	scene, ok := event.Scene()
+	if !ok {
+		return "", false
+	}
+	return obj.Campaign(scene)
+}
+
+func (obj *QrCodeAttribution) sceneKey(scene string) string {
+	return obj.cacheKey("qrcode.scene", obj.officialAccount.AppID, scene)
+}
@@ -0,0 +1,68 @@
+package webox
+
+import (
+	"encoding/xml"
+	"testing"
+	"webox/cache"
+	"webox/model"
+)
+
+// TestValidateQrCodeAction ...
+func TestValidateQrCodeAction(t *testing.T) {
+	tests := []struct {
+		action *model.QrCodeAction
+		valid  bool
+	}{
+		{&model.QrCodeAction{ExpireSeconds: 60, ActionName: model.QrScene, ActionInfo: model.QrCodeActionInfo{Scene: &model.QrCodeScene{SceneID: 1}}}, true},
+		{&model.QrCodeAction{ExpireSeconds: 2592001, ActionName: model.QrStrScene, ActionInfo: model.QrCodeActionInfo{Scene: &model.QrCodeScene{SceneStr: "a"}}}, false},
+		{&model.QrCodeAction{ActionName: model.QrLimitScene, ActionInfo: model.QrCodeActionInfo{Scene: &model.QrCodeScene{SceneID: 100001}}}, false},
+		{&model.QrCodeAction{ActionName: model.QrLimitStrScene, ActionInfo: model.QrCodeActionInfo{Scene: &model.QrCodeScene{}}}, false},
+		{&model.QrCodeAction{ActionName: model.QrCard, ActionInfo: model.QrCodeActionInfo{Card: &model.QrCodeCard{CardID: "card"}}}, true},
+	}
+	for i, tt := range tests {
+		if err := ValidateQrCodeAction(tt.action); (err == nil) != tt.valid {
+			t.Errorf("case %d: unexpected result %v", i, err)
+		}
+	}
+}
+
+// TestQrCodeAttribution_Resolve ...
+func TestQrCodeAttribution_Resolve(t *testing.T) {
+	attribution := NewOfficialAccount(&OfficialAccountProperty{AppID: "wx"}).QrCodeAttribution()
+	attribution.Bind("spring", "campaign-1", 0)
+
+	events := map[string]string{
+		"<xml><Event><![CDATA[subscribe]]></Event><EventKey><![CDATA[qrscene_spring]]></EventKey></xml>": "campaign-1",
+		"<xml><Event><![CDATA[SCAN]]></Event><EventKey><![CDATA[spring]]></EventKey></xml>":              "campaign-1",
+		"<xml><Event><![CDATA[subscribe]]></Event><EventKey><![CDATA[]]></EventKey></xml>":               "",
+		"<xml><Event><![CDATA[SCAN]]></Event><EventKey><![CDATA[autumn]]></EventKey></xml>":              "",
+	}
+	for body, want := range events {
+		var event model.QrCodeScanEvent
+		if err := xml.Unmarshal([]byte(body), &event); err != nil {
+			t.Fatal(err)
+		}
+		if got, ok := attribution.Resolve(&event); got != want || ok != (want != "") {
+			t.Errorf("%s: got %q %v, want %q", body, got, ok, want)
+		}
+	}
+}
+
+// TestQrCodeAttributionCache 归因记录保存在指定的缓存实例及命名空间中
+func TestQrCodeAttributionCache(t *testing.T) {
+	c := cache.NewMapCache()
+	oa := NewOfficialAccount(&OfficialAccountProperty{AppID: "wx"})
+	tenant1 := oa.QrCodeAttribution(QrCodeAttributionCache(c, "tenant1"))
+	tenant1.Bind("summer", "campaign-2", 0)
+
+	if !c.Has("tenant1.qrcode.scene.wx.summer") || cache.Has("webox.qrcode.scene.wx.summer") {
+		t.Fatal("attribution should be stored only in the instance cache")
+	}
+	// 共享同一缓存的另一进程可找回活动ID
+	if got, ok := oa.QrCodeAttribution(QrCodeAttributionCache(c, "tenant1")).Campaign("summer"); !ok || got != "campaign-2" {
+		t.Fatalf("got %q %v, want campaign-2", got, ok)
+	}
+	if _, ok := oa.QrCodeAttribution(QrCodeAttributionCache(c, "tenant2")).Campaign("summer"); ok {
+		t.Fatal("attribution should not leak across namespaces")
+	}
+}
@@ -0,0 +1,130 @@
+package webox
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"webox/api"
+	"webox/model"
+	"webox/util"
+)
+
+// SubscribeGetCategory 获取公众号类目
+// http请求方式: GET
+// https://api.weixin.qq.com/wxaapi/newtmpl/getcategory?access_token=ACCESS_TOKEN
+func (obj *OfficialAccount) SubscribeGetCategory() (list []*model.SubscribeCategory, e error) {
+
+	u := util.URL(obj.RemoteURL(), api.SubscribeGetCategory)
+	resp := obj.Client().Get(context.Background(), u, nil)
+	if e = resp.Error(); e != nil {
+		return nil, e
+	}
+	var result model.SubscribeCategoryList
+	if e = resp.Unmarshal(&result); e != nil {
+		return nil, e
+	}
+	return result.Data, nil
+}
+
+// SubscribeGetPubTemplateTitles 获取类目下的公共模板
+// ids: 类目 id，多个用逗号隔开; start: 用于分页，表示从 start 开始，从 0 开始计数; limit: 用于分页，表示拉取 limit 条记录，最大为 30
+// http请求方式: GET
+// https://api.weixin.qq.com/wxaapi/newtmpl/getpubtemplatetitles?access_token=ACCESS_TOKEN
+func (obj *OfficialAccount) SubscribeGetPubTemplateTitles(ids []int, start, limit int) (list *model.SubscribePubTemplateTitleList, e error) {
+
+	var s []string
+	for _, id := range ids {
+		s = append(s, strconv.Itoa(id))
+	}
+	u := util.URL(obj.RemoteURL(), api.SubscribeGetPubTemplateTitles)
+	resp := obj.Client().Get(context.Background(), u, util.Map{
+		"ids":   strings.Join(s, ","),
+		"start": strconv.Itoa(start),
+		"limit": strconv.Itoa(limit),
+	})
+	if e = resp.Error(); e != nil {
+		return nil, e
+	}
+	list = new(model.SubscribePubTemplateTitleList)
+	if e = resp.Unmarshal(list); e != nil {
+		return nil, e
+	}
+	return list, nil
+}
+
+// SubscribeGetPubTemplateKeywords 获取模板中的关键词
+// http请求方式: GET
+// https://api.weixin.qq.com/wxaapi/newtmpl/getpubtemplatekeywords?access_token=ACCESS_TOKEN&tid=TID
+func (obj *OfficialAccount) SubscribeGetPubTemplateKeywords(tid string) (list []*model.SubscribeKeyword, e error) {
+
+	u := util.URL(obj.RemoteURL(), api.SubscribeGetPubTemplateKeywords)
+	resp := obj.Client().Get(context.Background(), u, util.Map{"tid": tid})
+	if e = resp.Error(); e != nil {
+		return nil, e
+	}
+	var result model.SubscribeKeywordList
+	if e = resp.Unmarshal(&result); e != nil {
+		return nil, e
+	}
+	return result.Data, nil
+}
+
+// SubscribeAddTemplate 选用模板
+// tid: 模板标题 id; kidList: 开发者自行组合好的模板关键词列表，关键词顺序可以自由搭配，最多支持5个，最少2个关键词组合
+// sceneDesc: 服务场景描述，15个字以内
+// http请求方式: POST
+// https://api.weixin.qq.com/wxaapi/newtmpl/addtemplate?access_token=ACCESS_TOKEN
+func (obj *OfficialAccount) SubscribeAddTemplate(tid string, kidList []int, sceneDesc string) (priTmplID string, e error) {
+
+	u := util.URL(obj.RemoteURL(), api.SubscribeAddTemplate)
+	resp := obj.Client().Post(context.Background(), u, nil, util.Map{
+		"tid":       tid,
+		"kidList":   kidList,
+		"sceneDesc": sceneDesc,
+	})
+	if e = resp.Error(); e != nil {
+		return "", e
+	}
+	var result struct {
+		PriTmplID string `json:"priTmplId"`
+	}
+	if e = resp.Unmarshal(&result); e != nil {
+		return "", e
+	}
+	return result.PriTmplID, nil
+}
+
+// SubscribeDelTemplate 删除模板
+// http请求方式: POST
+// https://api.weixin.qq.com/wxaapi/newtmpl/deltemplate?access_token=ACCESS_TOKEN
+func (obj *OfficialAccount) SubscribeDelTemplate(priTmplID string) Responder {
+
+	u := util.URL(obj.RemoteURL(), api.SubscribeDelTemplate)
+	return obj.Client().Post(context.Background(), u, nil, util.Map{"priTmplId": priTmplID})
+}
+
+// SubscribeGetTemplate 获取私有模板列表
+// http请求方式: GET
+// https://api.weixin.qq.com/wxaapi/newtmpl/gettemplate?access_token=ACCESS_TOKEN
+func (obj *OfficialAccount) SubscribeGetTemplate() (list []*model.SubscribeTemplate, e error) {
+
+	u := util.URL(obj.RemoteURL(), api.SubscribeGetTemplate)
+	resp := obj.Client().Get(context.Background(), u, nil)
+	if e = resp.Error(); e != nil {
+		return nil, e
+	}
+	var result model.SubscribeTemplateList
+	if e = resp.Unmarshal(&result); e != nil {
+		return nil, e
+	}
+	return result.Data, nil
+}
+
+// SubscribeSend 发送订阅通知
+// http请求方式: POST
+// https://api.weixin.qq.com/cgi-bin/message/subscribe/bizsend?access_token=ACCESS_TOKEN
+func (obj *OfficialAccount) SubscribeSend(msg *model.SubscribeMessage) Responder {
+
+	u := util.URL(obj.RemoteURL(), api.SendMessageSubscribe)
+	return obj.Client().Post(context.Background(), u, nil, msg)
+}
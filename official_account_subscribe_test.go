@@ -0,0 +1,161 @@
+package webox
+
+import (
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"webox/cipher"
+	"webox/model"
+	"webox/util"
+)
+
+// signedNotifyURL 生成明文模式下带签名的消息推送地址
+func signedNotifyURL(token string) string {
+	ts, nonce := "1610969440", "nonce"
+	return "/notify?" + url.Values{
+		"signature": {util.GenSHA1(token, ts, nonce)},
+		"timestamp": {ts},
+		"nonce":     {nonce},
+	}.Encode()
+}
+
+// TestOfficialAccount_HandleMessage_SubscribeMsgPopupEvent ...
+func TestOfficialAccount_HandleMessage_SubscribeMsgPopupEvent(t *testing.T) {
+	body := `<xml>
+<ToUserName><![CDATA[gh_123456789abc]]></ToUserName>
+<FromUserName><![CDATA[otFpruAK8D-E6EfStSYonYSBZ8_4]]></FromUserName>
+<CreateTime>1610969440</CreateTime>
+<MsgType><![CDATA[event]]></MsgType>
+<Event><![CDATA[subscribe_msg_popup_event]]></Event>
+<SubscribeMsgPopupEvent>
+<List>
+<TemplateId><![CDATA[VRR0UEO9VJOLs0MHlU0OilqX6MVFDwH3_3gz3Oc0NIc]]></TemplateId>
+<SubscribeStatusString><![CDATA[accept]]></SubscribeStatusString>
+<PopupScene>2</PopupScene>
+</List>
+<List>
+<TemplateId><![CDATA[9nLIlbOQZC5Y89AZteFEux3WCXRRRG5Wfzkpssu4bLI]]></TemplateId>
+<SubscribeStatusString><![CDATA[reject]]></SubscribeStatusString>
+<PopupScene>2</PopupScene>
+</List>
+</SubscribeMsgPopupEvent>
+</xml>`
+
+	var event model.SubscribeMsgPopupEvent
+	oa := NewOfficialAccount(&OfficialAccountProperty{AppID: "wx", Token: "token"})
+	handler := oa.HandleMessage(func(req Requester) (util.Map, error) {
+		return nil, req.Unmarshal(&event)
+	})
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("POST", signedNotifyURL("token"), strings.NewReader(body)))
+
+	if w.Body.String() != "success" {
+		t.Fatalf("unexpected reply %q", w.Body.String())
+	}
+	if event.Event != model.EventTypeSubscribeMsgPopup || event.FromUserName != "otFpruAK8D-E6EfStSYonYSBZ8_4" {
+		t.Fatalf("unexpected event header %+v", event.EventMessage)
+	}
+	if len(event.List) != 2 || event.List[1].SubscribeStatusString != "reject" || event.List[0].PopupScene != 2 {
+		t.Fatalf("unexpected event list %+v", event.List)
+	}
+}
+
+// TestOfficialAccount_HandleMessage_Signature 签名错误或缺失时拒绝推送
+func TestOfficialAccount_HandleMessage_Signature(t *testing.T) {
+	body := `<xml><ToUserName><![CDATA[gh]]></ToUserName><FromUserName><![CDATA[o1]]></FromUserName>
+<CreateTime>1610969440</CreateTime><MsgType><![CDATA[text]]></MsgType><Content><![CDATA[hi]]></Content></xml>`
+	called := false
+	oa := NewOfficialAccount(&OfficialAccountProperty{AppID: "wx", Token: "token"})
+	handler := oa.HandleMessage(func(req Requester) (util.Map, error) {
+		called = true
+		return nil, nil
+	})
+
+	for _, target := range []string{"/notify", signedNotifyURL("other"), signedNotifyURL("other") + "&echostr=echo"} {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest("POST", target, strings.NewReader(body)))
+		if w.Code != http.StatusForbidden || called || strings.Contains(w.Body.String(), "echo") {
+			t.Fatalf("%s: got status %d, called %v", target, w.Code, called)
+		}
+	}
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", signedNotifyURL("token")+"&echostr=echo", nil))
+	if w.Body.String() != "echo" {
+		t.Fatalf("unexpected echo reply %q", w.Body.String())
+	}
+}
+
+// TestOfficialAccount_HandleMessage_Aes 安全模式下校验msg_signature，解密消息并加密回复
+func TestOfficialAccount_HandleMessage_Aes(t *testing.T) {
+	const aesKey = "abcdefghijklmnopqrstuvwxyz0123456789ABCDEFG"
+	biz := cipher.New(cipher.BizMsg, cipher.OptionKey(aesKey), cipher.OptionToken("token"), cipher.OptionID("wx"))
+	encrypt := func(text string) *cipher.BizMsgData {
+		b, e := biz.Encrypt(&cipher.BizMsgData{Text: text, TimeStamp: "1610969440", Nonce: "nonce"})
+		if e != nil {
+			t.Fatal(e)
+		}
+		var data cipher.BizMsgData
+		if e = xml.Unmarshal(b, &data); e != nil {
+			t.Fatal(e)
+		}
+		return &data
+	}
+
+	var content string
+	oa := NewOfficialAccount(&OfficialAccountProperty{AppID: "wx", Token: "token", AesKey: aesKey})
+	handler := oa.HandleMessage(func(req Requester) (util.Map, error) {
+		msg := req.ToMap()
+		content = msg.GetString("Content")
+		return util.Map{"ToUserName": msg.GetString("FromUserName"), "FromUserName": msg.GetString("ToUserName"), "MsgType": "text", "Content": "reply"}, nil
+	})
+
+	data := encrypt(`<xml><ToUserName><![CDATA[gh]]></ToUserName><FromUserName><![CDATA[o1]]></FromUserName>
+<CreateTime>1610969440</CreateTime><MsgType><![CDATA[text]]></MsgType><Content><![CDATA[hi]]></Content></xml>`)
+	body := `<xml><ToUserName><![CDATA[gh]]></ToUserName><Encrypt><![CDATA[` + data.RSAEncrypt + `]]></Encrypt></xml>`
+	target := func(signature string) string {
+		return "/notify?" + url.Values{
+			"encrypt_type":  {"aes"},
+			"msg_signature": {signature},
+			"timestamp":     {data.TimeStamp},
+			"nonce":         {data.Nonce},
+		}.Encode()
+	}
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("POST", target("bad"), strings.NewReader(body)))
+	if w.Code != http.StatusForbidden || content != "" {
+		t.Fatalf("got status %d, content %q", w.Code, content)
+	}
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("POST", target(data.MsgSignature), strings.NewReader(body)))
+	if content != "hi" {
+		t.Fatalf("unexpected decrypted content %q", content)
+	}
+	reply := util.Map{}
+	if e := xml.Unmarshal(w.Body.Bytes(), &reply); e != nil {
+		t.Fatal(e)
+	}
+	if reply.Has("Content") || util.GenSHA1("token", reply.GetString("TimeStamp"), reply.GetString("Nonce"), reply.GetString("Encrypt")) != reply.GetString("MsgSignature") {
+		t.Fatalf("unexpected reply %s", w.Body.String())
+	}
+	text, e := xml.Marshal(&cipher.BizMsgData{
+		RSAEncrypt:   reply.GetString("Encrypt"),
+		TimeStamp:    reply.GetString("TimeStamp"),
+		Nonce:        reply.GetString("Nonce"),
+		MsgSignature: reply.GetString("MsgSignature"),
+	})
+	if e != nil {
+		t.Fatal(e)
+	}
+	plain, e := biz.Decrypt(&cipher.BizMsgData{Text: string(text)})
+	if e != nil || !strings.Contains(string(plain), "<Content><![CDATA[reply]]></Content>") {
+		t.Fatalf("unexpected decrypted reply %s %v", plain, e)
+	}
+}
@@ -0,0 +1,312 @@
+package webox
+
+import (
+	"context"
+	"iter"
+	"maps"
+	"slices"
+	"webox/api"
+	"webox/model"
+	"webox/util"
+)
+
+// TagBatchMaxCount 批量为用户打标签/取消标签每次最多传入的openid个数
+const TagBatchMaxCount = 50
+
+// BlackListBatchMaxCount 拉黑/取消拉黑用户每次最多传入的openid个数
+const BlackListBatchMaxCount = 20
+
+// Tags 获取公众号已创建的标签
+// http请求方式:GET（请使用https协议）
+// https://api.weixin.qq.com/cgi-bin/tags/get?access_token=ACCESS_TOKEN
+func (obj *OfficialAccount) Tags() (tags []*model.Tag, e error) {
+
+	resp := obj.TagGet()
+	if e = resp.Error(); e != nil {
+		return nil, e
+	}
+	var result model.TagList
+	if e = resp.Unmarshal(&result); e != nil {
+		return nil, e
+	}
+	return result.Tags, nil
+}
+
+// TagUpdate 编辑标签
+// http请求方式:POST（请使用https协议）
+// https://api.weixin.qq.com/cgi-bin/tags/update?access_token=ACCESS_TOKEN
+func (obj *OfficialAccount) TagUpdate(id int, name string) Responder {
+
+	u := util.URL(obj.RemoteURL(), api.TagsUpdate)
+	return obj.Client().Post(context.Background(), u, nil, util.Map{"tag": util.Map{"id": id, "name": name}})
+}
+
+// TagDelete 删除标签
+// 当某个标签下的粉丝超过10w时，后台不可直接删除标签，需先取消该标签下的粉丝后再删除
+// http请求方式:POST（请使用https协议）
+// https://api.weixin.qq.com/cgi-bin/tags/delete?access_token=ACCESS_TOKEN
+func (obj *OfficialAccount) TagDelete(id int) Responder {
+
+	u := util.URL(obj.RemoteURL(), api.TagsDelete)
+	return obj.Client().Post(context.Background(), u, nil, util.Map{"tag": util.Map{"id": id}})
+}
+
+// TagMemberList 获取标签下粉丝列表
+// nextOpenid: 第一个拉取的OPENID，不填默认从头开始拉取，每次最多拉取10000个
+// http请求方式:POST（请使用https协议）
+// https://api.weixin.qq.com/cgi-bin/user/tag/get?access_token=ACCESS_TOKEN
+func (obj *OfficialAccount) TagMemberList(tagID int, nextOpenid string) (list *model.UserList, e error) {
+
+	u := util.URL(obj.RemoteURL(), api.UserTagGet)
+	resp := obj.Client().Post(context.Background(), u, nil, util.Map{"tagid": tagID, "next_openid": nextOpenid})
+	if e = resp.Error(); e != nil {
+		return nil, e
+	}
+	list = new(model.UserList)
+	if e = resp.Unmarshal(list); e != nil {
+		return nil, e
+	}
+	return list, nil
+}
+
+// TagMembers 遍历标签下的全部粉丝OpenID
+func (obj *OfficialAccount) TagMembers(tagID int) iter.Seq2[string, error] {
+	return openIDPages("", func(next string) (*model.UserList, error) {
+		return obj.TagMemberList(tagID, next)
+	})
+}
+
+// TagBatchTagging 批量为用户打标签，超过50个openid时自动分批调用
+// http请求方式:POST（请使用https协议）
+// https://api.weixin.qq.com/cgi-bin/tags/members/batchtagging?access_token=ACCESS_TOKEN
+func (obj *OfficialAccount) TagBatchTagging(tagID int, openids []string) error {
+
+	u := util.URL(obj.RemoteURL(), api.TagsMembersBatchTagging)
+	for chunk := range slices.Chunk(openids, TagBatchMaxCount) {
+		resp := obj.Client().Post(context.Background(), u, nil, util.Map{"openid_list": chunk, "tagid": tagID})
+		if e := resp.Error(); e != nil {
+			return e
+		}
+	}
+	return nil
+}
+
+// TagBatchUntagging 批量为用户取消标签，超过50个openid时自动分批调用
+// http请求方式:POST（请使用https协议）
+// https://api.weixin.qq.com/cgi-bin/tags/members/batchuntagging?access_token=ACCESS_TOKEN
+func (obj *OfficialAccount) TagBatchUntagging(tagID int, openids []string) error {
+
+	u := util.URL(obj.RemoteURL(), api.TagsMembersBatchUntagging)
+	for chunk := range slices.Chunk(openids, TagBatchMaxCount) {
+		resp := obj.Client().Post(context.Background(), u, nil, util.Map{"openid_list": chunk, "tagid": tagID})
+		if e := resp.Error(); e != nil {
+			return e
+		}
+	}
+	return nil
+}
+
+// TagIDList 获取用户身上的标签列表
+// http请求方式:POST（请使用https协议）
+// https://api.weixin.qq.com/cgi-bin/tags/getidlist?access_token=ACCESS_TOKEN
+func (obj *OfficialAccount) TagIDList(openid string) (ids []int, e error) {
+
+	u := util.URL(obj.RemoteURL(), api.TagsGetIDList)
+	resp := obj.Client().Post(context.Background(), u, nil, util.Map{"openid": openid})
+	if e = resp.Error(); e != nil {
+		return nil, e
+	}
+	var result model.TagIDList
+	if e = resp.Unmarshal(&result); e != nil {
+		return nil, e
+	}
+	return result.TagIDList, nil
+}
+
+// BlackList 获取公众号的黑名单列表
+// beginOpenid: 为空时，默认从开头拉取，每次最多拉取10000个
+// http请求方式:POST（请使用https协议）
+// https://api.weixin.qq.com/cgi-bin/tags/members/getblacklist?access_token=ACCESS_TOKEN
+func (obj *OfficialAccount) BlackList(beginOpenid string) (list *model.UserList, e error) {
+
+	u := util.URL(obj.RemoteURL(), api.TagsMembersGetBlackList)
+	resp := obj.Client().Post(context.Background(), u, nil, util.Map{"begin_openid": beginOpenid})
+	if e = resp.Error(); e != nil {
+		return nil, e
+	}
+	list = new(model.UserList)
+	if e = resp.Unmarshal(list); e != nil {
+		return nil, e
+	}
+	return list, nil
+}
+
+// BlackListOpenIDs 遍历黑名单中的全部OpenID
+func (obj *OfficialAccount) BlackListOpenIDs() iter.Seq2[string, error] {
+	return openIDPages("", obj.BlackList)
+}
+
+// BlackListBatchAdd 拉黑用户，超过20个openid时自动分批调用
+// http请求方式:POST（请使用https协议）
+// https://api.weixin.qq.com/cgi-bin/tags/members/batchblacklist?access_token=ACCESS_TOKEN
+func (obj *OfficialAccount) BlackListBatchAdd(openids []string) error {
+
+	u := util.URL(obj.RemoteURL(), api.TagsMembersBatchBlackList)
+	for chunk := range slices.Chunk(openids, BlackListBatchMaxCount) {
+		resp := obj.Client().Post(context.Background(), u, nil, util.Map{"openid_list": chunk})
+		if e := resp.Error(); e != nil {
+			return e
+		}
+	}
+	return nil
+}
+
+// BlackListBatchRemove 取消拉黑用户，超过20个openid时自动分批调用
+// http请求方式:POST（请使用https协议）
+// https://api.weixin.qq.com/cgi-bin/tags/members/batchunblacklist?access_token=ACCESS_TOKEN
+func (obj *OfficialAccount) BlackListBatchRemove(openids []string) error {
+
+	u := util.URL(obj.RemoteURL(), api.TagsMembersBatchUnblackList)
+	for chunk := range slices.Chunk(openids, BlackListBatchMaxCount) {
+		resp := obj.Client().Post(context.Background(), u, nil, util.Map{"openid_list": chunk})
+		if e := resp.Error(); e != nil {
+			return e
+		}
+	}
+	return nil
+}
+
+// openIDPages 按next_openid翻页遍历OpenID列表
+func openIDPages(next string, page func(next string) (*model.UserList, error)) iter.Seq2[string, error] {
+	return func(yield func(string, error) bool) {
+		for {
+			list, e := page(next)
+			if e != nil {
+				yield("", e)
+				return
+			}
+			for _, openid := range list.Data.OpenID {
+				if !yield(openid, nil) {
+					return
+				}
+			}
+			if list.Count < UserGetMaxCount || list.NextOpenID == "" {
+				return
+			}
+			next = list.NextOpenID
+		}
+	}
+}
+
+// TagPlan 标签变更计划：标签ID到需要打上/取消该标签的openid列表
+type TagPlan struct {
+	Tagging   map[int][]string
+	Untagging map[int][]string
+}
+
+// PlanTags 比较用户当前标签与期望标签，生成变更计划
+// 仅处理desired中出现的用户，期望标签为空表示取消该用户的全部标签。
+// 变更按标签合并，每个标签每50个用户只需一次调用
+func PlanTags(current, desired map[string][]int) *TagPlan {
+	plan := &TagPlan{
+		Tagging:   make(map[int][]string),
+		Untagging: make(map[int][]string),
+	}
+	for openid, want := range desired {
+		have := current[openid]
+		for _, id := range want {
+			if !slices.Contains(have, id) {
+				plan.Tagging[id] = append(plan.Tagging[id], openid)
+			}
+		}
+		for _, id := range have {
+			if !slices.Contains(want, id) {
+				plan.Untagging[id] = append(plan.Untagging[id], openid)
+			}
+		}
+	}
+	for _, openids := range plan.Tagging {
+		slices.Sort(openids)
+	}
+	for _, openids := range plan.Untagging {
+		slices.Sort(openids)
+	}
+	return plan
+}
+
+// Calls 执行计划所需的接口调用次数
+func (obj *TagPlan) Calls() int {
+	calls := 0
+	for _, openids := range obj.Tagging {
+		calls += (len(openids) + TagBatchMaxCount - 1) / TagBatchMaxCount
+	}
+	for _, openids := range obj.Untagging {
+		calls += (len(openids) + TagBatchMaxCount - 1) / TagBatchMaxCount
+	}
+	return calls
+}
+
+// TagApply 执行标签变更计划，先取消标签再打标签，避免超出每个用户最多20个标签的限制
+func (obj *OfficialAccount) TagApply(plan *TagPlan) error {
+	for _, id := range slices.Sorted(maps.Keys(plan.Untagging)) {
+		if e := obj.TagBatchUntagging(id, plan.Untagging[id]); e != nil {
+			return e
+		}
+	}
+	for _, id := range slices.Sorted(maps.Keys(plan.Tagging)) {
+		if e := obj.TagBatchTagging(id, plan.Tagging[id]); e != nil {
+			return e
+		}
+	}
+	return nil
+}
+
+// TagMapping 获取用户当前的标签
+// 当遍历全部标签的粉丝列表所需的调用次数少于逐个用户查询时，改为遍历标签粉丝列表
+func (obj *OfficialAccount) TagMapping(openids []string) (map[string][]int, error) {
+	tags, e := obj.Tags()
+	if e != nil {
+		return nil, e
+	}
+	calls := 0
+	for _, tag := range tags {
+		calls += max(1, (tag.Count+UserGetMaxCount-1)/UserGetMaxCount)
+	}
+
+	mapping := make(map[string][]int, len(openids))
+	if calls >= len(openids) {
+		for _, openid := range openids {
+			ids, e := obj.TagIDList(openid)
+			if e != nil {
+				return nil, e
+			}
+			mapping[openid] = ids
+		}
+		return mapping, nil
+	}
+
+	for _, openid := range openids {
+		mapping[openid] = nil
+	}
+	for _, tag := range tags {
+		for openid, e := range obj.TagMembers(tag.ID) {
+			if e != nil {
+				return nil, e
+			}
+			if ids, ok := mapping[openid]; ok {
+				mapping[openid] = append(ids, tag.ID)
+			}
+		}
+	}
+	return mapping, nil
+}
+
+// TagReconcile 将desired中用户的标签收敛为期望的标签，返回执行的变更计划
+func (obj *OfficialAccount) TagReconcile(desired map[string][]int) (*TagPlan, error) {
+	current, e := obj.TagMapping(slices.Collect(maps.Keys(desired)))
+	if e != nil {
+		return nil, e
+	}
+	plan := PlanTags(current, desired)
+	return plan, obj.TagApply(plan)
+}
@@ -0,0 +1,262 @@
+package webox
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"testing"
+
+	"webox/api"
+	"webox/model"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// testTags 模拟标签及黑名单接口，保存每个标签的粉丝及黑名单，并记录批量接口每次传入的openid个数
+type testTags struct {
+	mu        sync.Mutex
+	members   map[int][]string
+	blacklist []string
+	batches   map[string][]int
+	calls     map[string]int
+}
+
+func newTestTags(t *testing.T, members map[int][]string) (*OfficialAccount, *testTags) {
+	t.Helper()
+	tags := &testTags{members: members, batches: map[string][]int{}, calls: map[string]int{}}
+	srv := httptest.NewServer(http.HandlerFunc(tags.serve))
+	t.Cleanup(srv.Close)
+	return testOfficialAccountAt(srv.URL), tags
+}
+
+func (obj *testTags) serve(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		TagID       int      `json:"tagid"`
+		OpenID      string   `json:"openid"`
+		OpenIDList  []string `json:"openid_list"`
+		NextOpenID  string   `json:"next_openid"`
+		BeginOpenID string   `json:"begin_openid"`
+	}
+	_ = jsoniter.NewDecoder(r.Body).Decode(&req)
+	obj.mu.Lock()
+	defer obj.mu.Unlock()
+	obj.calls[r.URL.Path]++
+
+	var resp any = map[string]any{"errcode": 0, "errmsg": "ok"}
+	switch r.URL.Path {
+	case api.GetTags:
+		list := model.TagList{}
+		for id, openids := range obj.members {
+			list.Tags = append(list.Tags, &model.Tag{ID: id, Name: "tag", Count: len(openids)})
+		}
+		resp = list
+	case api.UserTagGet:
+		resp = testOpenIDPage(obj.members[req.TagID], req.NextOpenID)
+	case api.TagsMembersGetBlackList:
+		resp = testOpenIDPage(obj.blacklist, req.BeginOpenID)
+	case api.TagsGetIDList:
+		ids := []int{}
+		for id, openids := range obj.members {
+			if slices.Contains(openids, req.OpenID) {
+				ids = append(ids, id)
+			}
+		}
+		slices.Sort(ids)
+		resp = model.TagIDList{TagIDList: ids}
+	case api.TagsMembersBatchTagging:
+		for _, openid := range req.OpenIDList {
+			if !slices.Contains(obj.members[req.TagID], openid) {
+				obj.members[req.TagID] = append(obj.members[req.TagID], openid)
+			}
+		}
+	case api.TagsMembersBatchUntagging:
+		obj.members[req.TagID] = slices.DeleteFunc(obj.members[req.TagID], func(openid string) bool {
+			return slices.Contains(req.OpenIDList, openid)
+		})
+	case api.TagsMembersBatchBlackList:
+		obj.blacklist = append(obj.blacklist, req.OpenIDList...)
+	case api.TagsMembersBatchUnblackList:
+		obj.blacklist = slices.DeleteFunc(obj.blacklist, func(openid string) bool {
+			return slices.Contains(req.OpenIDList, openid)
+		})
+	}
+	if req.OpenIDList != nil {
+		obj.batches[r.URL.Path] = append(obj.batches[r.URL.Path], len(req.OpenIDList))
+	}
+	_ = jsoniter.NewEncoder(w).Encode(resp)
+}
+
+// testOpenIDPage 从next之后返回至多UserGetMaxCount个openid，next_openid为本页最后一个openid
+func testOpenIDPage(openids []string, next string) *model.UserList {
+	if i := slices.Index(openids, next); next != "" && i >= 0 {
+		openids = openids[i+1:]
+	}
+	page := openids[:min(len(openids), UserGetMaxCount)]
+	list := &model.UserList{Total: len(openids), Count: len(page), Data: model.UserOpenIDList{OpenID: page}}
+	if len(page) > 0 {
+		list.NextOpenID = page[len(page)-1]
+	}
+	return list
+}
+
+func (obj *testTags) snapshot() (map[int][]string, map[string][]int, map[string]int) {
+	obj.mu.Lock()
+	defer obj.mu.Unlock()
+	members := make(map[int][]string, len(obj.members))
+	for id, openids := range obj.members {
+		members[id] = slices.Sorted(slices.Values(openids))
+	}
+	batches := make(map[string][]int, len(obj.batches))
+	for path, sizes := range obj.batches {
+		batches[path] = slices.Clone(sizes)
+	}
+	calls := make(map[string]int, len(obj.calls))
+	for path, n := range obj.calls {
+		calls[path] = n
+	}
+	return members, batches, calls
+}
+
+// TestPlanTags ...
+func TestPlanTags(t *testing.T) {
+	current := map[string][]int{
+		"o1": {100, 101},
+		"o2": {100},
+		"o3": {102},
+	}
+	desired := map[string][]int{
+		"o1": {100, 103},
+		"o2": {100, 103},
+		"o3": {},
+		"o4": {103},
+	}
+
+	plan := PlanTags(current, desired)
+	if !slices.Equal(plan.Tagging[103], []string{"o1", "o2", "o4"}) || len(plan.Tagging) != 1 {
+		t.Fatalf("unexpected tagging %v", plan.Tagging)
+	}
+	if !slices.Equal(plan.Untagging[101], []string{"o1"}) || !slices.Equal(plan.Untagging[102], []string{"o3"}) || len(plan.Untagging) != 2 {
+		t.Fatalf("unexpected untagging %v", plan.Untagging)
+	}
+	if plan.Calls() != 3 {
+		t.Fatalf("unexpected calls %d", plan.Calls())
+	}
+}
+
+// TestOfficialAccount_TagBatch 打标签每次最多50个openid，拉黑每次最多20个openid
+func TestOfficialAccount_TagBatch(t *testing.T) {
+	oa, tags := newTestTags(t, map[int][]string{100: nil})
+	ids := testOpenIDs(120)
+
+	if e := oa.TagBatchTagging(100, ids); e != nil {
+		t.Fatal(e)
+	}
+	if e := oa.TagBatchUntagging(100, ids[:51]); e != nil {
+		t.Fatal(e)
+	}
+	if e := oa.BlackListBatchAdd(ids[:41]); e != nil {
+		t.Fatal(e)
+	}
+	if e := oa.BlackListBatchRemove(ids[:20]); e != nil {
+		t.Fatal(e)
+	}
+
+	members, batches, _ := tags.snapshot()
+	for path, want := range map[string][]int{
+		api.TagsMembersBatchTagging:     {50, 50, 20},
+		api.TagsMembersBatchUntagging:   {50, 1},
+		api.TagsMembersBatchBlackList:   {20, 20, 1},
+		api.TagsMembersBatchUnblackList: {20},
+	} {
+		if !slices.Equal(batches[path], want) {
+			t.Fatalf("%s got batches %v, want %v", path, batches[path], want)
+		}
+	}
+	if len(members[100]) != 69 {
+		t.Fatalf("got %d tag members, want 69", len(members[100]))
+	}
+}
+
+// TestOfficialAccount_TagMembers 标签粉丝及黑名单按next_openid翻页遍历
+func TestOfficialAccount_TagMembers(t *testing.T) {
+	ids := testOpenIDs(UserGetMaxCount + 1)
+	oa, tags := newTestTags(t, map[int][]string{100: ids})
+	tags.blacklist = ids
+
+	for name, seq := range map[string]func(yield func(string, error) bool){
+		api.UserTagGet:              oa.TagMembers(100),
+		api.TagsMembersGetBlackList: oa.BlackListOpenIDs(),
+	} {
+		var got []string
+		for openid, e := range seq {
+			if e != nil {
+				t.Fatal(e)
+			}
+			got = append(got, openid)
+		}
+		if !slices.Equal(got, ids) {
+			t.Fatalf("%s got %d openids, want %d", name, len(got), len(ids))
+		}
+	}
+
+	_, _, calls := tags.snapshot()
+	if calls[api.UserTagGet] != 2 || calls[api.TagsMembersGetBlackList] != 2 {
+		t.Fatalf("unexpected page requests %v", calls)
+	}
+}
+
+// TestOfficialAccount_TagMapping 用户少时逐个查询，遍历标签粉丝更省调用时改为遍历
+func TestOfficialAccount_TagMapping(t *testing.T) {
+	members := map[int][]string{100: {"o1", "o2"}, 101: {"o2", "o9"}}
+
+	oa, tags := newTestTags(t, members)
+	mapping, e := oa.TagMapping([]string{"o1", "o2"})
+	if e != nil {
+		t.Fatal(e)
+	}
+	if !slices.Equal(mapping["o1"], []int{100}) || !slices.Equal(mapping["o2"], []int{100, 101}) {
+		t.Fatalf("unexpected mapping %v", mapping)
+	}
+	if _, _, calls := tags.snapshot(); calls[api.TagsGetIDList] != 2 || calls[api.UserTagGet] != 0 {
+		t.Fatalf("expected per-user lookups, got %v", calls)
+	}
+
+	oa, tags = newTestTags(t, members)
+	mapping, e = oa.TagMapping([]string{"o1", "o2", "o3"})
+	if e != nil {
+		t.Fatal(e)
+	}
+	slices.Sort(mapping["o2"])
+	if !slices.Equal(mapping["o1"], []int{100}) || !slices.Equal(mapping["o2"], []int{100, 101}) || mapping["o3"] != nil || len(mapping) != 3 {
+		t.Fatalf("unexpected mapping %v", mapping)
+	}
+	if _, _, calls := tags.snapshot(); calls[api.TagsGetIDList] != 0 || calls[api.UserTagGet] != 2 {
+		t.Fatalf("expected tag member traversal, got %v", calls)
+	}
+}
+
+// TestOfficialAccount_TagReconcile 收敛后服务端标签与期望一致，未出现在desired中的用户不受影响
+func TestOfficialAccount_TagReconcile(t *testing.T) {
+	oa, tags := newTestTags(t, map[int][]string{100: {"o1", "o2", "o9"}, 101: {"o1"}, 102: {"o3"}, 103: nil})
+	plan, e := oa.TagReconcile(map[string][]int{
+		"o1": {100, 103},
+		"o2": {100, 103},
+		"o3": {},
+		"o4": {103},
+	})
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	members, batches, _ := tags.snapshot()
+	want := map[int][]string{100: {"o1", "o2", "o9"}, 101: {}, 102: {}, 103: {"o1", "o2", "o4"}}
+	for id, openids := range want {
+		if !slices.Equal(members[id], openids) {
+			t.Fatalf("tag %d got members %v, want %v", id, members[id], openids)
+		}
+	}
+	if calls := len(batches[api.TagsMembersBatchTagging]) + len(batches[api.TagsMembersBatchUntagging]); calls != plan.Calls() || calls != 3 {
+		t.Fatalf("got %d batch calls, plan expects %d", calls, plan.Calls())
+	}
+}
@@ -2,6 +2,7 @@ package webox
 
 import (
 	"context"
+	"iter"
 	"webox/api"
 	"webox/model"
 	"webox/util"
@@ -24,10 +25,16 @@ import (
 // {"errcode":40013,"errmsg":"invalid appid"}
 func (obj *OfficialAccount) UserUpdateRemark(openid, remark string) Responder {
 
-	u := util.URL(api.UserInfoUpdateRemark)
+	u := util.URL(obj.RemoteURL(), api.UserInfoUpdateRemark)
 	return obj.Client().Post(context.Background(), u, nil, util.Map{"openid": openid, "remark": remark})
 }
 
+// UserGetMaxCount 获取用户列表每次最多拉取的OPENID个数
+const UserGetMaxCount = 10000
+
+// UserBatchGetMaxCount 批量获取用户基本信息每次最多拉取的用户数
+const UserBatchGetMaxCount = 100
+
 // UserInfo 获取用户信息
 // 接口调用请求说明
 // http请求方式: GET
@@ -40,7 +47,7 @@ func (obj *OfficialAccount) UserInfo(openid, lang string) (info *model.UserInfo,
 	if lang != "" {
 		p.Set("lang", lang)
 	}
-	u := util.URL(api.UserInfo)
+	u := util.URL(obj.RemoteURL(), api.UserInfo)
 	resp := obj.Client().Get(context.Background(), u, p)
 	if e = resp.Error(); e != nil {
 		return nil, e
@@ -61,8 +68,12 @@ func (obj *OfficialAccount) UserInfo(openid, lang string) (info *model.UserInfo,
 // 失败:
 // {"errcode":40013,"errmsg":"invalid appid"}
 func (obj *OfficialAccount) UserBatchGet(openids []string, lang string) (infos []*model.UserInfo, e error) {
+	return obj.userBatchGet(context.Background(), openids, lang)
+}
 
-	u := util.URL(api.UserInfoBatchGet)
+func (obj *OfficialAccount) userBatchGet(ctx context.Context, openids []string, lang string) (infos []*model.UserInfo, e error) {
+
+	u := util.URL(obj.RemoteURL(), api.UserInfoBatchGet)
 	var list []*model.UserID
 	for _, v := range openids {
 		if lang != "" {
@@ -72,7 +83,7 @@ func (obj *OfficialAccount) UserBatchGet(openids []string, lang string) (infos [
 		}
 
 	}
-	resp := obj.Client().Post(context.Background(), u, nil, util.Map{"user_list": list})
+	resp := obj.Client().Post(ctx, u, nil, util.Map{"user_list": list})
 	if e = resp.Error(); e != nil {
 		return nil, e
 	}
@@ -88,10 +99,40 @@ func (obj *OfficialAccount) UserBatchGet(openids []string, lang string) (infos [
 // http请求方式: GET（请使用https协议）
 // https://api.weixin.qq.com/cgi-bin/user/get?access_token=ACCESS_TOKEN&next_openid=NEXT_OPENID
 func (obj *OfficialAccount) UserGet(nextOpenid string) Responder {
+	return obj.userGet(context.Background(), nextOpenid)
+}
 
-	u := util.URL(api.UserGet)
+func (obj *OfficialAccount) userGet(ctx context.Context, nextOpenid string) Responder {
+
+	u := util.URL(obj.RemoteURL(), api.UserGet)
 	if nextOpenid == "" {
-		return obj.Client().Get(context.Background(), u, nil)
+		return obj.Client().Get(ctx, u, nil)
+	}
+	return obj.Client().Get(ctx, u, util.Map{"next_openid": nextOpenid})
+}
+
+// UserList 获取用户列表
+// 一次拉取调用最多拉取10000个关注者的OpenID，nextOpenid为空时默认从头开始拉取
+// http请求方式: GET（请使用https协议）
+// https://api.weixin.qq.com/cgi-bin/user/get?access_token=ACCESS_TOKEN&next_openid=NEXT_OPENID
+func (obj *OfficialAccount) UserList(nextOpenid string) (list *model.UserList, e error) {
+	return obj.userList(context.Background(), nextOpenid)
+}
+
+func (obj *OfficialAccount) userList(ctx context.Context, nextOpenid string) (list *model.UserList, e error) {
+
+	resp := obj.userGet(ctx, nextOpenid)
+	if e = resp.Error(); e != nil {
+		return nil, e
+	}
+	list = new(model.UserList)
+	if e = resp.Unmarshal(list); e != nil {
+		return nil, e
 	}
-	return obj.Client().Get(context.Background(), u, util.Map{"next_openid": nextOpenid})
+	return list, nil
+}
+
+// UserOpenIDs 遍历nextOpenid之后的全部关注者OpenID，自动按next_openid翻页
+func (obj *OfficialAccount) UserOpenIDs(nextOpenid string) iter.Seq2[string, error] {
+	return openIDPages(nextOpenid, obj.UserList)
 }
@@ -0,0 +1,147 @@
+package webox
+
+import (
+	"context"
+	"sync"
+	"time"
+	"webox/model"
+)
+
+// UserSink 接收同步到的用户信息，同一时刻只会被一个goroutine调用
+type UserSink func(info *model.UserInfo) error
+
+// UserSync 全量同步关注者信息
+// 按next_openid逐页拉取关注者列表，每页内按100个一批并发调用批量获取用户基本信息接口，
+// 一页全部写入sink后才会保存检查点，中断后从检查点恢复最多重复同步一页数据
+type UserSync struct {
+	officialAccount *OfficialAccount
+	concurrency     int
+	interval        time.Duration
+	lang            string
+	nextOpenid      string
+	save            func(nextOpenid string) error
+}
+
+// UserSync ...
+func (obj *OfficialAccount) UserSync(options ...UserSyncOption) *UserSync {
+	syncer := &UserSync{
+		officialAccount: obj,
+		concurrency:     1,
+	}
+	for _, o := range options {
+		o(syncer)
+	}
+	if syncer.concurrency < 1 {
+		syncer.concurrency = 1
+	}
+	return syncer
+}
+
+// Run 开始同步，直至全部关注者处理完毕、ctx结束或出现错误
+func (obj *UserSync) Run(ctx context.Context, sink UserSink) error {
+	var tick <-chan time.Time
+	if obj.interval > 0 {
+		ticker := time.NewTicker(obj.interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	next := obj.nextOpenid
+	for {
+		if e := ctx.Err(); e != nil {
+			return e
+		}
+		list, e := obj.officialAccount.userList(ctx, next)
+		if e != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return e
+		}
+		if e = obj.page(ctx, list.Data.OpenID, tick, sink); e != nil {
+			return e
+		}
+		if list.NextOpenID != "" {
+			next = list.NextOpenID
+			if obj.save != nil {
+				if e = obj.save(next); e != nil {
+					return e
+				}
+			}
+		}
+		if list.Count < UserGetMaxCount || list.NextOpenID == "" {
+			return nil
+		}
+	}
+}
+
+func (obj *UserSync) page(parent context.Context, openids []string, tick <-chan time.Time, sink UserSink) error {
+	ctx, cancel := context.WithCancel(parent)
+	defer cancel()
+
+	batches := make(chan []string)
+	go func() {
+		defer close(batches)
+		for len(openids) > 0 {
+			n := min(len(openids), UserBatchGetMaxCount)
+			select {
+			case batches <- openids[:n]:
+			case <-ctx.Done():
+				return
+			}
+			openids = openids[n:]
+		}
+	}()
+
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		err  error
+		fail = func(e error) {
+			mu.Lock()
+			if err == nil {
+				err = e
+			}
+			mu.Unlock()
+			cancel()
+		}
+	)
+	for range obj.concurrency {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for batch := range batches {
+				if tick != nil {
+					select {
+					case <-tick:
+					case <-ctx.Done():
+						return
+					}
+				}
+				infos, e := obj.officialAccount.userBatchGet(ctx, batch, obj.lang)
+				if e != nil {
+					fail(e)
+					return
+				}
+				mu.Lock()
+				for _, info := range infos {
+					if e = sink(info); e != nil {
+						break
+					}
+				}
+				mu.Unlock()
+				if e != nil {
+					fail(e)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	//外部取消时请求错误只是取消的结果，返回ctx的错误
+	if e := parent.Err(); e != nil {
+		return e
+	}
+	return err
+}
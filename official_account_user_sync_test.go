@@ -0,0 +1,212 @@
+package webox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"webox/api"
+	"webox/model"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// testFollowers 模拟关注者列表及批量获取用户信息接口
+type testFollowers struct {
+	openids    []string
+	mu         sync.Mutex
+	lists      []string     // 每次拉取列表请求的next_openid
+	batches    atomic.Int32 // 批量获取用户信息的请求次数
+	onBatchGet func(r *http.Request)
+}
+
+func newTestFollowers(n int) *testFollowers {
+	openids := make([]string, n)
+	for i := range openids {
+		openids[i] = fmt.Sprintf("o%05d", i)
+	}
+	return &testFollowers{openids: openids}
+}
+
+func (f *testFollowers) listRequests() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return slices.Clone(f.lists)
+}
+
+func (f *testFollowers) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	switch r.URL.Path {
+	case api.UserGet:
+		next := r.URL.Query().Get("next_openid")
+		f.mu.Lock()
+		f.lists = append(f.lists, next)
+		f.mu.Unlock()
+		start := 0
+		if next != "" {
+			start = slices.Index(f.openids, next) + 1
+		}
+		page := f.openids[start:min(start+UserGetMaxCount, len(f.openids))]
+		list := model.UserList{Total: len(f.openids), Count: len(page)}
+		list.Data.OpenID = page
+		if len(page) > 0 {
+			list.NextOpenID = page[len(page)-1]
+		}
+		_ = jsoniter.NewEncoder(w).Encode(list)
+	case api.UserInfoBatchGet:
+		f.batches.Add(1)
+		body, _ := io.ReadAll(r.Body)
+		if f.onBatchGet != nil {
+			f.onBatchGet(r)
+		}
+		var req struct {
+			UserList []*model.UserID `json:"user_list"`
+		}
+		_ = jsoniter.Unmarshal(body, &req)
+		var result model.UserInfoList
+		for _, id := range req.UserList {
+			result.UserInfoList = append(result.UserInfoList, &model.UserInfo{Openid: id.OpenID, Subscribe: 1})
+		}
+		_ = jsoniter.NewEncoder(w).Encode(result)
+	}
+}
+
+// TestUserSync_Run 并发同步全部关注者，每页写入完成后才保存检查点
+func TestUserSync_Run(t *testing.T) {
+	followers := newTestFollowers(UserGetMaxCount + 5)
+	srv := httptest.NewServer(followers)
+	defer srv.Close()
+
+	var synced []string
+	var checkpoints []string
+	syncer := testOfficialAccountAt(srv.URL).UserSync(UserSyncConcurrency(4), UserSyncCheckpoint("", func(next string) error {
+		checkpoints = append(checkpoints, fmt.Sprintf("%s@%d", next, len(synced)))
+		return nil
+	}))
+	e := syncer.Run(context.Background(), func(info *model.UserInfo) error {
+		synced = append(synced, info.Openid)
+		return nil
+	})
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	slices.Sort(synced)
+	if !slices.Equal(synced, followers.openids) {
+		t.Fatalf("synced %d users, want %d", len(synced), len(followers.openids))
+	}
+	want := []string{fmt.Sprintf("o%05d@%d", UserGetMaxCount-1, UserGetMaxCount), fmt.Sprintf("o%05d@%d", UserGetMaxCount+4, UserGetMaxCount+5)}
+	if !slices.Equal(checkpoints, want) {
+		t.Fatalf("got checkpoints %v, want %v", checkpoints, want)
+	}
+	if n := followers.batches.Load(); n != 101 {
+		t.Fatalf("got %d batchget requests, want 101", n)
+	}
+}
+
+// TestUserSync_Resume 从检查点恢复时只同步检查点之后的关注者
+func TestUserSync_Resume(t *testing.T) {
+	followers := newTestFollowers(250)
+	srv := httptest.NewServer(followers)
+	defer srv.Close()
+
+	var synced []string
+	syncer := testOfficialAccountAt(srv.URL).UserSync(UserSyncCheckpoint("o00099", nil))
+	e := syncer.Run(context.Background(), func(info *model.UserInfo) error {
+		synced = append(synced, info.Openid)
+		return nil
+	})
+	if e != nil {
+		t.Fatal(e)
+	}
+	if !slices.Equal(followers.listRequests(), []string{"o00099"}) {
+		t.Fatalf("unexpected list requests %v", followers.listRequests())
+	}
+	if !slices.Equal(synced, followers.openids[100:]) {
+		t.Fatalf("synced %d users from %s, want 150 from o00100", len(synced), synced[0])
+	}
+}
+
+// TestUserSync_Interval 每批请求之间至少间隔interval
+func TestUserSync_Interval(t *testing.T) {
+	followers := newTestFollowers(250)
+	srv := httptest.NewServer(followers)
+	defer srv.Close()
+
+	interval := 30 * time.Millisecond
+	start := time.Now()
+	syncer := testOfficialAccountAt(srv.URL).UserSync(UserSyncConcurrency(3), UserSyncInterval(interval))
+	if e := syncer.Run(context.Background(), func(*model.UserInfo) error { return nil }); e != nil {
+		t.Fatal(e)
+	}
+	if elapsed := time.Since(start); elapsed < 3*interval {
+		t.Fatalf("3 batches finished in %v, want at least %v", elapsed, 3*interval)
+	}
+}
+
+// TestUserSync_SinkError sink出错时停止同步且不保存检查点
+func TestUserSync_SinkError(t *testing.T) {
+	followers := newTestFollowers(UserGetMaxCount + 5)
+	srv := httptest.NewServer(followers)
+	defer srv.Close()
+
+	errSink := errors.New("sink failed")
+	saved := false
+	n := 0
+	syncer := testOfficialAccountAt(srv.URL).UserSync(UserSyncConcurrency(2), UserSyncCheckpoint("", func(string) error {
+		saved = true
+		return nil
+	}))
+	e := syncer.Run(context.Background(), func(*model.UserInfo) error {
+		if n++; n == 150 {
+			return errSink
+		}
+		return nil
+	})
+	if !errors.Is(e, errSink) || saved || n != 150 {
+		t.Fatalf("got %v, saved %v after %d users", e, saved, n)
+	}
+	if batches := followers.batches.Load(); batches > 4 {
+		t.Fatalf("sync continued with %d batchget requests", batches)
+	}
+	if len(followers.listRequests()) != 1 {
+		t.Fatalf("sync continued to next page %v", followers.listRequests())
+	}
+}
+
+// TestUserSync_Cancel 取消ctx时中止进行中的请求
+func TestUserSync_Cancel(t *testing.T) {
+	followers := newTestFollowers(250)
+	ctx, cancel := context.WithCancel(context.Background())
+	var once sync.Once
+	aborted := make(chan struct{})
+	followers.onBatchGet = func(r *http.Request) {
+		once.Do(cancel)
+		select {
+		case <-r.Context().Done():
+			close(aborted)
+		case <-time.After(5 * time.Second):
+		}
+	}
+	srv := httptest.NewServer(followers)
+	defer srv.Close()
+
+	start := time.Now()
+	e := testOfficialAccountAt(srv.URL).UserSync().Run(ctx, func(*model.UserInfo) error { return nil })
+	if !errors.Is(e, context.Canceled) || time.Since(start) > time.Second {
+		t.Fatalf("got %v after %v, want context.Canceled", e, time.Since(start))
+	}
+	select {
+	case <-aborted:
+	case <-time.After(time.Second):
+		t.Fatal("in-flight request was not aborted")
+	}
+}
@@ -4,7 +4,10 @@ import (
 	"context"
 	"crypto/tls"
 	"log"
+	"time"
 	"webox/api"
+	"webox/cache"
+	"webox/util"
 )
 
 // PaymentOption ...
@@ -47,6 +50,13 @@ func PaymentLocal(local string) PaymentOption {
 	}
 }
 
+// PaymentCache 使用独立的缓存实例及key命名空间缓存沙箱key，Sandbox设置了缓存时以Sandbox的为准
+func PaymentCache(c cache.Cache, namespace string) PaymentOption {
+	return func(obj *Payment) {
+		obj.setCache(c, namespace)
+	}
+}
+
 // PaymentSandbox ...
 func PaymentSandbox(sandbox *Sandbox) PaymentOption {
 	return func(obj *Payment) {
@@ -92,6 +102,27 @@ func AccessTokenURL(url string) AccessTokenOption {
 	}
 }
 
+// AccessTokenLocker 设置刷新token时使用的锁，多进程部署时应使用分布式锁
+func AccessTokenLocker(locker cache.Locker) AccessTokenOption {
+	return func(obj *AccessToken) {
+		obj.locker = locker
+	}
+}
+
+// AccessTokenProvider 设置获取token的来源，如稳定版token接口或中控服务
+func AccessTokenProvider(provider TokenProvider) AccessTokenOption {
+	return func(obj *AccessToken) {
+		obj.provider = provider
+	}
+}
+
+// AccessTokenCache 使用独立的缓存实例及key命名空间，c为nil时使用全局缓存，namespace为空时使用DefaultCacheNamespace
+func AccessTokenCache(c cache.Cache, namespace string) AccessTokenOption {
+	return func(obj *AccessToken) {
+		obj.setCache(c, namespace)
+	}
+}
+
 // AccessTokenKey ...
 func AccessTokenKey(key string) AccessTokenOption {
 	return func(obj *AccessToken) {
@@ -99,6 +130,16 @@ func AccessTokenKey(key string) AccessTokenOption {
 	}
 }
 
+// ServerTokenProviderOption ...
+type ServerTokenProviderOption func(obj *ServerTokenProvider)
+
+// ServerTokenProviderQuery 请求中控服务时附加的参数，如鉴权信息
+func ServerTokenProviderQuery(query util.Map) ServerTokenProviderOption {
+	return func(obj *ServerTokenProvider) {
+		obj.Query = query
+	}
+}
+
 // OfficialAccountOption ...
 type OfficialAccountOption func(obj *OfficialAccount)
 
@@ -123,6 +164,13 @@ func OfficialAccountAccessTokenProperty(property *AccessTokenProperty) OfficialA
 	}
 }
 
+// OfficialAccountTokenProvider 通过provider获取token
+func OfficialAccountTokenProvider(provider TokenProvider) OfficialAccountOption {
+	return func(obj *OfficialAccount) {
+		obj.AccessToken = NewAccessToken(&AccessTokenProperty{}, AccessTokenProvider(provider))
+	}
+}
+
 // OfficialAccountAccessToken ...
 func OfficialAccountAccessToken(token *AccessToken) OfficialAccountOption {
 	return func(obj *OfficialAccount) {
@@ -130,6 +178,14 @@ func OfficialAccountAccessToken(token *AccessToken) OfficialAccountOption {
 	}
 }
 
+// OfficialAccountCache 使用独立的缓存实例及key命名空间，
+// 作为未单独设置缓存的AccessToken、JSSDK及QrCodeAttribution的默认缓存
+func OfficialAccountCache(c cache.Cache, namespace string) OfficialAccountOption {
+	return func(obj *OfficialAccount) {
+		obj.setCache(c, namespace)
+	}
+}
+
 // OfficialAccountBodyType ...
 func OfficialAccountBodyType(bodyType BodyType) OfficialAccountOption {
 	return func(obj *OfficialAccount) {
@@ -168,6 +224,13 @@ func ClientAccessToken(token *AccessToken) ClientOption {
 	}
 }
 
+// ClientTokenProvider 通过provider获取token
+func ClientTokenProvider(provider TokenProvider) ClientOption {
+	return func(obj *Client) {
+		obj.AccessToken = NewAccessToken(&AccessTokenProperty{}, AccessTokenProvider(provider))
+	}
+}
+
 // ClientAccessTokenProperty ...
 func ClientAccessTokenProperty(property *AccessTokenProperty) ClientOption {
 	return func(obj *Client) {
@@ -206,6 +269,13 @@ func ClientBodyType(bt BodyType) ClientOption {
 // SandboxOption ...
 type SandboxOption func(obj *Sandbox)
 
+// SandboxCache 使用独立的缓存实例及key命名空间缓存沙箱key
+func SandboxCache(c cache.Cache, namespace string) SandboxOption {
+	return func(obj *Sandbox) {
+		obj.setCache(c, namespace)
+	}
+}
+
 // SandboxSubID ...
 func SandboxSubID(mch, app string) SandboxOption {
 	return func(obj *Sandbox) {
@@ -217,14 +287,28 @@ func SandboxSubID(mch, app string) SandboxOption {
 // JSSDKOption ...
 type JSSDKOption func(obj *JSSDK)
 
-// JSSDKAccessTokenProperty ...
+// JSSDKAccessTokenProperty 通过property创建AccessToken，token使用JSSDKCache设置的缓存
 func JSSDKAccessTokenProperty(property *AccessTokenProperty) JSSDKOption {
 	return func(obj *JSSDK) {
 		obj.AccessToken = NewAccessToken(property, AccessTokenKey(api.AccessTokenKey), AccessTokenURL(api.AccessToken))
 	}
 }
 
-// JSSDKAccessToken ...
+// JSSDKCache 使用独立的缓存实例及key命名空间缓存api_ticket
+func JSSDKCache(c cache.Cache, namespace string) JSSDKOption {
+	return func(obj *JSSDK) {
+		obj.setCache(c, namespace)
+	}
+}
+
+// JSSDKTokenProvider 通过provider获取token，token使用JSSDKCache设置的缓存
+func JSSDKTokenProvider(provider TokenProvider) JSSDKOption {
+	return func(obj *JSSDK) {
+		obj.AccessToken = NewAccessToken(&AccessTokenProperty{}, AccessTokenProvider(provider))
+	}
+}
+
+// JSSDKAccessToken token未设置AccessTokenCache时使用JSSDKCache设置的缓存
 func JSSDKAccessToken(token *AccessToken) JSSDKOption {
 	return func(obj *JSSDK) {
 		obj.AccessToken = token
@@ -244,3 +328,87 @@ func JSSDKURL(url string) JSSDKOption {
 		obj.url = url
 	}
 }
+
+// UserSyncOption ...
+type UserSyncOption func(obj *UserSync)
+
+// UserSyncConcurrency 同时进行的批量获取请求数
+func UserSyncConcurrency(n int) UserSyncOption {
+	return func(obj *UserSync) {
+		obj.concurrency = n
+	}
+}
+
+// UserSyncInterval 相邻两次批量获取请求之间的最小间隔，用于限制调用频率
+func UserSyncInterval(d time.Duration) UserSyncOption {
+	return func(obj *UserSync) {
+		obj.interval = d
+	}
+}
+
+// UserSyncLang 返回国家地区语言版本，zh_CN 简体，zh_TW 繁体，en 英语
+func UserSyncLang(lang string) UserSyncOption {
+	return func(obj *UserSync) {
+		obj.lang = lang
+	}
+}
+
+// UserSyncCheckpoint 从next_openid处继续同步，每处理完一页关注者后调用save保存新的检查点
+func UserSyncCheckpoint(nextOpenid string, save func(nextOpenid string) error) UserSyncOption {
+	return func(obj *UserSync) {
+		obj.nextOpenid = nextOpenid
+		obj.save = save
+	}
+}
+
+// DatacubeOption ...
+type DatacubeOption func(obj *Datacube)
+
+// DatacubeConcurrency 同时进行的查询请求数
+func DatacubeConcurrency(n int) DatacubeOption {
+	return func(obj *Datacube) {
+		obj.concurrency = n
+	}
+}
+
+// QrCodeAttributionOption ...
+type QrCodeAttributionOption func(obj *QrCodeAttribution)
+
+// QrCodeAttributionCache 使用独立的缓存实例及key命名空间保存归因记录
+func QrCodeAttributionCache(c cache.Cache, namespace string) QrCodeAttributionOption {
+	return func(obj *QrCodeAttribution) {
+		obj.setCache(c, namespace)
+	}
+}
+
+// RefresherOption ...
+type RefresherOption func(obj *Refresher)
+
+// RefresherMargin 在凭证过期前margin时刷新
+func RefresherMargin(margin time.Duration) RefresherOption {
+	return func(obj *Refresher) {
+		obj.margin = margin
+	}
+}
+
+// RefresherJitter 随机提前刷新的比例，取值[0,1)
+func RefresherJitter(jitter float64) RefresherOption {
+	return func(obj *Refresher) {
+		obj.jitter = jitter
+	}
+}
+
+// RefresherBackoff 刷新失败后的重试间隔从minBackoff开始倍增，至多maxBackoff
+func RefresherBackoff(minBackoff, maxBackoff time.Duration) RefresherOption {
+	return func(obj *Refresher) {
+		obj.minBackoff = minBackoff
+		obj.maxBackoff = maxBackoff
+	}
+}
+
+// RefresherNotify 设置接收刷新结果的回调
+func RefresherNotify(notify RefreshNotify) RefresherOption {
+	return func(obj *Refresher) {
+		obj.notify = notify
+	}
+}
@@ -28,6 +28,7 @@ type Payment struct {
 	notifyURL   string
 	refundedURL string
 	scannedURL  string
+	cacheScope
 }
 
 // NewPayment ...
@@ -951,23 +952,29 @@ func (obj *Payment) UseSandbox() bool {
 	return obj.sandbox != nil
 }
 
+// sandboxCache 沙箱key的缓存，Sandbox未设置缓存时使用Payment的缓存
+func (obj *Payment) sandboxCache() (string, cache.Cache) {
+	scope := &obj.sandbox.cacheScope
+	if scope.isDefault() {
+		scope = &obj.cacheScope
+	}
+	return obj.sandbox.getCacheKey(scope), scope.getCache()
+}
+
 /*GetKey 沙箱key(string类型) */
 func (obj *Payment) GetKey() string {
 	key := obj.Key
 	if obj.UseSandbox() {
-		keyName := obj.sandbox.getCacheKey()
-		cachedKey := cache.Get(keyName)
-		if cachedKey != nil {
-
-			key = cachedKey.(string)
-			return key
+		keyName, c := obj.sandboxCache()
+		if cachedKey, b := c.Get(keyName).(string); b {
+			return cachedKey
 		}
 
 		resp := obj.sandbox.SignKey().ToMap()
 		if resp.GetString("return_code") == "SUCCESS" {
 			key = resp.GetString("sandbox_signkey")
 
-			cache.Set(keyName, key, time.Duration(24*time.Hour))
+			c.Set(keyName, key, time.Duration(24*time.Hour))
 		}
 	}
 
@@ -0,0 +1,42 @@
+package qrcode
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// TestReedSolomonRemainder 1-M "HELLO WORLD" 的数据码字及纠错码字
+func TestReedSolomonRemainder(t *testing.T) {
+	data := []byte{32, 91, 11, 120, 209, 114, 220, 77, 67, 64, 236, 17, 236, 17, 236, 17}
+	want := []byte{196, 35, 39, 119, 235, 215, 231, 226, 93, 23}
+	if got := reedSolomonRemainder(data, reedSolomonDivisor(len(want))); !bytes.Equal(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+// TestEncode ...
+func TestEncode(t *testing.T) {
+	q, err := Encode("http://weixin.qq.com/q/028MgH1oLwdyi1ySjNxq17", LevelM)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q.Version != 4 || q.Size() != 33 {
+		t.Fatalf("unexpected version %d size %d", q.Version, q.Size())
+	}
+	// 左上角定位图形
+	for i := range 7 {
+		if !q.Dark(i, 0) || !q.Dark(0, i) || q.Dark(7, i) {
+			t.Fatalf("unexpected finder pattern at %d", i)
+		}
+	}
+
+	var svg bytes.Buffer
+	if err = q.WriteSVG(&svg, 4); err != nil || !strings.Contains(svg.String(), `viewBox="0 0 41 41"`) {
+		t.Fatalf("unexpected svg %v %s", err, svg.String())
+	}
+	if _, err = Encode(strings.Repeat("x", 3000), LevelL); !errors.Is(err, ErrTooLong) {
+		t.Fatalf("expected ErrTooLong, got %v", err)
+	}
+}
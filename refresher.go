@@ -0,0 +1,166 @@
+package webox
+
+import (
+	"context"
+	"math/rand/v2"
+	"time"
+)
+
+// RefreshFunc 执行一次刷新，返回新凭证的有效期
+type RefreshFunc func(ctx context.Context) (expiresIn time.Duration, e error)
+
+// RefreshResult 一次刷新的结果
+type RefreshResult struct {
+	Name      string        // 刷新的对象，如access_token、jsapi、wx_card
+	ExpiresIn time.Duration // 新凭证的有效期，失败时为0
+	Next      time.Duration // 距下次刷新的时间
+	Failures  int           // 连续失败的次数，成功时为0
+	Err       error
+}
+
+// RefreshNotify 接收每次刷新的结果，可用于告警
+type RefreshNotify func(result *RefreshResult)
+
+// DefaultRefreshMargin 默认在凭证过期前10分钟刷新，大于缓存时预留的AccessTokenSafeSeconds
+const DefaultRefreshMargin = 10 * time.Minute
+
+// RefreshMinInterval 两次刷新的最小间隔，防止有效期过短时频繁刷新
+const RefreshMinInterval = 10 * time.Second
+
+// Refresher 在凭证过期前主动刷新，避免请求时才同步获取
+// 下次刷新时间为有效期减去margin后再随机提前至多jitter比例，多个进程的刷新因此会错开；
+// 刷新失败时按指数退避重试，直至成功或ctx结束
+type Refresher struct {
+	name       string
+	refresh    RefreshFunc
+	margin     time.Duration
+	jitter     float64
+	minBackoff time.Duration
+	maxBackoff time.Duration
+	notify     RefreshNotify
+}
+
+// NewRefresher ...
+func NewRefresher(name string, refresh RefreshFunc, options ...RefresherOption) *Refresher {
+	refresher := &Refresher{
+		name:       name,
+		refresh:    refresh,
+		margin:     DefaultRefreshMargin,
+		jitter:     0.1,
+		minBackoff: time.Second,
+		maxBackoff: 5 * time.Minute,
+	}
+	for _, o := range options {
+		o(refresher)
+	}
+	return refresher
+}
+
+// Run 立即刷新一次，之后按有效期定时刷新，直至ctx结束
+func (obj *Refresher) Run(ctx context.Context) {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	failures := 0
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+
+		expiresIn, e := obj.refresh(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		result := &RefreshResult{Name: obj.name, Err: e}
+		if e != nil {
+			failures++
+			result.Next = obj.backoff(failures)
+		} else {
+			failures = 0
+			result.ExpiresIn = expiresIn
+			result.Next = obj.schedule(expiresIn)
+		}
+		result.Failures = failures
+		if obj.notify != nil {
+			obj.notify(result)
+		}
+		timer.Reset(result.Next)
+	}
+}
+
+// schedule 计算成功后距下次刷新的时间
+func (obj *Refresher) schedule(expiresIn time.Duration) time.Duration {
+	next := expiresIn - obj.margin
+	if obj.jitter > 0 {
+		next -= time.Duration(rand.Float64() * obj.jitter * float64(next))
+	}
+	return max(next, RefreshMinInterval)
+}
+
+// backoff 计算失败后的重试间隔，在[d/2, d)内随机
+func (obj *Refresher) backoff(failures int) time.Duration {
+	d := obj.minBackoff
+	for i := 1; i < failures && d < obj.maxBackoff; i++ {
+		d *= 2
+	}
+	d = min(d, obj.maxBackoff)
+	return d/2 + time.Duration(rand.Int64N(int64(d/2)+1))
+}
+
+// Refresher 在token过期前主动刷新access_token
+// 每次先读取缓存，剩余有效期大于margin时直接使用；否则在锁的保护下以非强制方式刷新，
+// 拿到锁后若token已被其他进程刷新则直接使用，多个进程共享缓存时每个周期只会请求一次。
+// stable_token等接口非强制刷新时可能返回同一个临近过期的token，此时改为强制刷新
+func (obj *AccessToken) Refresher(options ...RefresherOption) *Refresher {
+	refresher := NewRefresher("access_token", nil, options...)
+	fresh := func(current *Token) bool {
+		return current.Remaining() > refresher.margin
+	}
+	refresher.refresh = func(ctx context.Context) (time.Duration, error) {
+		if token := obj.cachedToken(obj.getCacheKey()); token != nil && fresh(token) {
+			return token.Remaining(), nil
+		}
+		token, e := obj.renewTokenUnless(ctx, false, fresh)
+		if e == nil && !fresh(token) {
+			token, e = obj.renewTokenUnless(ctx, true, fresh)
+		}
+		if e != nil {
+			return 0, e
+		}
+		return token.Remaining(), nil
+	}
+	return refresher
+}
+
+// Refresher 定时获取s类型(jsapi,wx_card)的api_ticket并交由store保存
+func (t *Ticket) Refresher(s string, store func(tr *TicketRes), options ...RefresherOption) *Refresher {
+	return NewRefresher(s, func(ctx context.Context) (time.Duration, error) {
+		tr, e := t.getTicketRes(ctx, s)
+		if e != nil {
+			return 0, e
+		}
+		if store != nil {
+			store(tr)
+		}
+		return time.Duration(tr.ExpiresIn) * time.Second, nil
+	}, options...)
+}
+
+// TicketRefresher 在缓存的s类型(jsapi,wx_card)api_ticket过期前主动刷新
+// 缓存中的ticket剩余有效期大于margin时直接使用，共享缓存的其他进程已刷新时不会重复请求
+func (obj *JSSDK) TicketRefresher(s string, options ...RefresherOption) *Refresher {
+	refresher := NewRefresher(s, nil, options...)
+	refresher.refresh = func(ctx context.Context) (time.Duration, error) {
+		if tr := obj.cachedTicket(obj.getCacheKey(s)); tr != nil && tr.Remaining() > refresher.margin {
+			return tr.Remaining(), nil
+		}
+		tr, e := obj.renewTicket(ctx, s)
+		if e != nil {
+			return 0, e
+		}
+		return tr.Remaining(), nil
+	}
+	return refresher
+}
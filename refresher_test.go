@@ -0,0 +1,203 @@
+package webox
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+	"webox/cache"
+)
+
+// TestRefresher_Run 失败时退避重试，成功后按有效期提前margin调度，ctx结束时退出
+func TestRefresher_Run(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	var results []*RefreshResult
+	refresher := NewRefresher("test", func(ctx context.Context) (time.Duration, error) {
+		calls++
+		if calls <= 2 {
+			return 0, errors.New("unavailable")
+		}
+		return time.Hour, nil
+	},
+		RefresherMargin(10*time.Minute),
+		RefresherJitter(0.1),
+		RefresherBackoff(time.Millisecond, 4*time.Millisecond),
+		RefresherNotify(func(result *RefreshResult) {
+			results = append(results, result)
+			if result.Err == nil {
+				cancel()
+			}
+		}),
+	)
+
+	done := make(chan struct{})
+	go func() {
+		refresher.Run(ctx)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("refresher did not stop after cancel")
+	}
+
+	if len(results) != 3 || results[0].Failures != 1 || results[1].Failures != 2 || results[2].Failures != 0 {
+		t.Fatalf("unexpected results %+v", results)
+	}
+	if results[1].Next >= 2*time.Millisecond+1 || results[1].Next < time.Millisecond {
+		t.Fatalf("unexpected backoff %v", results[1].Next)
+	}
+	if next := results[2].Next; next > 50*time.Minute || next < 45*time.Minute {
+		t.Fatalf("unexpected schedule %v", next)
+	}
+}
+
+// countingTokenProvider 记录请求次数及是否强制刷新
+type countingTokenProvider struct {
+	mu     sync.Mutex
+	forced []bool
+}
+
+func (p *countingTokenProvider) Key() string {
+	return "counting"
+}
+
+func (p *countingTokenProvider) RequestToken(ctx context.Context, forceRefresh bool) (*Token, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.forced = append(p.forced, forceRefresh)
+	return &Token{AccessToken: "token" + strconv.Itoa(len(p.forced)), ExpiresIn: 7200}, nil
+}
+
+func (p *countingTokenProvider) requests() []bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]bool(nil), p.forced...)
+}
+
+// runRefresherOnce 执行一次刷新后停止
+func runRefresherOnce(t *testing.T, newRefresher func(options ...RefresherOption) *Refresher) *RefreshResult {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var result *RefreshResult
+	newRefresher(RefresherNotify(func(r *RefreshResult) {
+		result = r
+		cancel()
+	})).Run(ctx)
+	if result == nil || result.Err != nil {
+		t.Fatalf("unexpected refresh result %+v", result)
+	}
+	return result
+}
+
+// TestAccessToken_RefresherSharedCache 共享缓存的多个refresher只在token临近过期时以非强制方式请求一次
+func TestAccessToken_RefresherSharedCache(t *testing.T) {
+	provider := &countingTokenProvider{}
+	c := cache.NewMapCache()
+	pod1 := NewAccessToken(&AccessTokenProperty{}, AccessTokenProvider(provider), AccessTokenCache(c, "shared"))
+	pod2 := NewAccessToken(&AccessTokenProperty{}, AccessTokenProvider(provider), AccessTokenCache(c, "shared"))
+
+	for _, at := range []*AccessToken{pod1, pod2, pod1} {
+		result := runRefresherOnce(t, at.Refresher)
+		if result.ExpiresIn < 7100*time.Second {
+			t.Fatalf("unexpected remaining lifetime %v", result.ExpiresIn)
+		}
+	}
+	if got := provider.requests(); len(got) != 1 || got[0] {
+		t.Fatalf("got token requests %v, want a single non-forced request", got)
+	}
+
+	// token剩余有效期小于margin时刷新，另一进程随后直接使用新token
+	pod1.setToken(&Token{AccessToken: "old", Expiry: time.Now().Add(5 * time.Minute)})
+	runRefresherOnce(t, pod2.Refresher)
+	runRefresherOnce(t, pod1.Refresher)
+	if got := provider.requests(); len(got) != 2 || got[1] {
+		t.Fatalf("got token requests %v, want one more non-forced request", got)
+	}
+	if token, e := pod1.GetToken(); e != nil || token.AccessToken != "token2" {
+		t.Fatalf("got %v %v, want token2", token, e)
+	}
+}
+
+// TestJSSDK_TicketRefresherCached 缓存中的ticket有效期充足时不请求新ticket
+func TestJSSDK_TicketRefresherCached(t *testing.T) {
+	c := cache.NewMapCache()
+	jssdk := NewJSSDK(&JSSDKProperty{AppID: "wx"}, JSSDKCache(c, "shared"))
+	c.Set(jssdk.getCacheKey("jsapi"), &TicketRes{Ticket: "ticket", ExpiresIn: 7200, Expiry: time.Now().Add(2 * time.Hour)}, time.Hour)
+
+	result := runRefresherOnce(t, func(options ...RefresherOption) *Refresher {
+		return NewJSSDK(&JSSDKProperty{AppID: "wx"}, JSSDKCache(c, "shared")).TicketRefresher("jsapi", options...)
+	})
+	if result.ExpiresIn < 7100*time.Second {
+		t.Fatalf("unexpected remaining lifetime %v", result.ExpiresIn)
+	}
+	if ticket := jssdk.GetTicket("jsapi", false); ticket != "ticket" {
+		t.Fatalf("got ticket %q", ticket)
+	}
+}
+
+// stableTokenProvider 非强制刷新时返回同一个临近过期的token，与stable_token接口的普通模式相同
+type stableTokenProvider struct {
+	countingTokenProvider
+}
+
+func (p *stableTokenProvider) RequestToken(ctx context.Context, forceRefresh bool) (*Token, error) {
+	token, _ := p.countingTokenProvider.RequestToken(ctx, forceRefresh)
+	if !forceRefresh {
+		return &Token{AccessToken: "stale", ExpiresIn: 300}, nil
+	}
+	return token, nil
+}
+
+// TestAccessToken_RefresherStable 非强制刷新得到的token仍在margin内时强制刷新
+func TestAccessToken_RefresherStable(t *testing.T) {
+	provider := &stableTokenProvider{}
+	at := NewAccessToken(&AccessTokenProperty{}, AccessTokenProvider(provider), AccessTokenCache(cache.NewMapCache(), "stable"))
+
+	result := runRefresherOnce(t, at.Refresher)
+	if result.ExpiresIn < 7100*time.Second {
+		t.Fatalf("unexpected remaining lifetime %v", result.ExpiresIn)
+	}
+	if got := provider.requests(); len(got) != 2 || got[0] || !got[1] {
+		t.Fatalf("got token requests %v, want a non-forced then a forced request", got)
+	}
+}
+
+// blockingTokenProvider 请求直至ctx结束
+type blockingTokenProvider struct {
+	started chan struct{}
+}
+
+func (p *blockingTokenProvider) Key() string {
+	return "blocking"
+}
+
+func (p *blockingTokenProvider) RequestToken(ctx context.Context, forceRefresh bool) (*Token, error) {
+	close(p.started)
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+// TestAccessToken_RefresherCancel ctx结束时取消进行中的刷新请求
+func TestAccessToken_RefresherCancel(t *testing.T) {
+	provider := &blockingTokenProvider{started: make(chan struct{})}
+	at := NewAccessToken(&AccessTokenProperty{}, AccessTokenProvider(provider), AccessTokenCache(cache.NewMapCache(), "blocking"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		at.Refresher().Run(ctx)
+		close(done)
+	}()
+	<-provider.started
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("refresher did not cancel the in-flight request")
+	}
+}
@@ -3,9 +3,13 @@ package webox
 import (
 	"bytes"
 	"encoding/xml"
+	"errors"
 	"io"
 	"log"
+	"mime/multipart"
 	"net/http"
+	"os"
+	"path/filepath"
 	"strings"
 	"webox/util"
 
@@ -35,7 +39,6 @@ type RequestBody struct {
 type RequestBuilderFunc func(method, url string, i any) (*http.Request, error)
 
 // TODO
-var buildMultipart = buildNothing
 var buildForm = buildNothing
 
 var builder = map[BodyType]RequestBuilderFunc{
@@ -90,6 +93,136 @@ func buildJSON(method, url string, i any) (*http.Request, error) {
 	return request, nil
 }
 
+// MultipartFile 以io.Reader上传的文件，Size未知时为-1（此时以chunked方式发送）
+type MultipartFile struct {
+	Name   string
+	Reader io.Reader
+	Size   int64
+}
+
+// multipartBody 按顺序拼接各部分的分隔头与文件内容，文件内容不会被读入内存
+type multipartBody struct {
+	io.Reader
+	closers     []io.Closer
+	size        int64
+	contentType string
+}
+
+// Close ...
+func (b *multipartBody) Close() error {
+	var err error
+	for _, c := range b.closers {
+		if e := c.Close(); e != nil && err == nil {
+			err = e
+		}
+	}
+	return err
+}
+
+// hasMultipartFile 判断是否包含只能读取一次的*MultipartFile
+func hasMultipartFile(m util.Map) bool {
+	for _, v := range m {
+		if _, b := v.(*MultipartFile); b {
+			return true
+		}
+	}
+	return false
+}
+
+// newMultipartBody 字段值为string时作为文件路径上传，*MultipartFile作为文件上传，其他值以JSON作为普通字段
+func newMultipartBody(m util.Map) (body *multipartBody, e error) {
+	var buf bytes.Buffer
+	var readers []io.Reader
+	var unknownSize bool
+	writer := multipart.NewWriter(&buf)
+	body = &multipartBody{contentType: writer.FormDataContentType()}
+	defer func() {
+		if e != nil {
+			_ = body.Close()
+		}
+	}()
+	flush := func() {
+		b := bytes.Clone(buf.Bytes())
+		buf.Reset()
+		readers = append(readers, bytes.NewReader(b))
+		body.size += int64(len(b))
+	}
+	file := func(field, name string, r io.Reader, size int64) error {
+		if _, e := writer.CreateFormFile(field, name); e != nil {
+			return e
+		}
+		flush()
+		readers = append(readers, r)
+		if size < 0 {
+			unknownSize = true
+		}
+		body.size += size
+		return nil
+	}
+
+	for _, k := range m.SortKeys() {
+		switch v := m[k].(type) {
+		case string:
+			f, e := os.Open(v)
+			if e != nil {
+				return nil, e
+			}
+			body.closers = append(body.closers, f)
+			info, e := f.Stat()
+			if e != nil {
+				return nil, e
+			}
+			if e = file(k, filepath.Base(v), f, info.Size()); e != nil {
+				return nil, e
+			}
+		case *MultipartFile:
+			if e = file(k, v.Name, v.Reader, v.Size); e != nil {
+				return nil, e
+			}
+		case util.Map:
+			if e = writer.WriteField(k, v.String()); e != nil {
+				return nil, e
+			}
+		default:
+			s, e := jsoniter.MarshalToString(v)
+			if e != nil {
+				return nil, e
+			}
+			if e = writer.WriteField(k, s); e != nil {
+				return nil, e
+			}
+		}
+	}
+	if e = writer.Close(); e != nil {
+		return nil, e
+	}
+	flush()
+	if unknownSize {
+		body.size = -1
+	}
+	body.Reader = io.MultiReader(readers...)
+	return body, nil
+}
+
+func buildMultipart(method, url string, i any) (*http.Request, error) {
+	m, b := i.(util.Map)
+	if !b {
+		return nil, errors.New("multipart body must be util.Map")
+	}
+	body, e := newMultipartBody(m)
+	if e != nil {
+		return nil, e
+	}
+	request, e := http.NewRequest(method, url, body)
+	if e != nil {
+		_ = body.Close()
+		return nil, e
+	}
+	request.ContentLength = body.size
+	request.Header.Set("Content-Type", body.contentType)
+	return request, nil
+}
+
 func buildNothing(method, url string, i any) (*http.Request, error) {
 	request, e := http.NewRequest(method, url, nil)
 	if e != nil {
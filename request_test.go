@@ -0,0 +1,48 @@
+package webox
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"testing"
+
+	"webox/util"
+)
+
+// TestBuildMultipart ...
+func TestBuildMultipart(t *testing.T) {
+	request, err := buildMultipart(http.MethodPost, "http://localhost/upload", util.Map{
+		"media":       &MultipartFile{Name: "a.jpg", Reader: strings.NewReader("JPEG"), Size: 4},
+		"description": util.Map{"title": "t"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, err := io.ReadAll(request.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if int64(len(body)) != request.ContentLength {
+		t.Fatalf("content length %d, body %d", request.ContentLength, len(body))
+	}
+
+	_, params, _ := mime.ParseMediaType(request.Header.Get("Content-Type"))
+	form, err := multipart.NewReader(bytes.NewReader(body), params["boundary"]).ReadForm(1 << 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := form.Value["description"]; len(v) != 1 || v[0] != `{"title":"t"}` {
+		t.Fatalf("unexpected description %v", v)
+	}
+	files := form.File["media"]
+	if len(files) != 1 || files[0].Filename != "a.jpg" {
+		t.Fatalf("unexpected files %v", files)
+	}
+	f, _ := files[0].Open()
+	if b, _ := io.ReadAll(f); string(b) != "JPEG" {
+		t.Fatalf("unexpected content %q", b)
+	}
+}
@@ -106,7 +106,7 @@ func (r *xmlResponse) Error() error {
 	}
 	_ = jsoniter.Unmarshal(r.bytes, &e)
 	if e.ErrCode != 0 {
-		return fmt.Errorf("code:%d,msg:%s", e.ErrCode, e.ErrMsg)
+		return &e
 	}
 	return nil
 }
@@ -169,7 +169,7 @@ func (r *jsonResponse) Error() error {
 	}
 	_ = jsoniter.Unmarshal(r.bytes, &e)
 	if e.ErrCode != 0 {
-		return fmt.Errorf("code:%d,msg:%s", e.ErrCode, e.ErrMsg)
+		return &e
 	}
 	return nil
 }
@@ -208,6 +208,34 @@ type ErrRes struct {
 	ErrMsg  string
 }
 
+// Error 微信接口返回的错误码，可通过errors.As取得
+func (e *ErrRes) Error() string {
+	return fmt.Sprintf("code:%d,msg:%s", e.ErrCode, e.ErrMsg)
+}
+
+// access_token无效或过期的错误码
+const (
+	ErrCodeInvalidCredential  = 40001 // 获取access_token时AppSecret错误，或者access_token无效
+	ErrCodeInvalidAccessToken = 40014 // 不合法的access_token
+	ErrCodeAccessTokenExpired = 42001 // access_token超时
+)
+
+// IsTokenInvalid 判断返回结果是否为access_token无效或过期的错误
+func IsTokenInvalid(resp Responder) bool {
+	if resp == nil {
+		return false
+	}
+	var e *ErrRes
+	if !errors.As(resp.Error(), &e) {
+		return false
+	}
+	switch e.ErrCode {
+	case ErrCodeInvalidCredential, ErrCodeInvalidAccessToken, ErrCodeAccessTokenExpired:
+		return true
+	}
+	return false
+}
+
 // Error ...
 func (r *Response) Error() error {
 	return r.err
@@ -258,6 +286,25 @@ func BuildResponder(resp *http.Response) Responder {
 	return ErrResponder(errors.New("error with code " + resp.Status))
 }
 
+// StreamTo 将二进制响应直接写入w；若响应为JSON（错误信息或JSON格式的内容），则不写入w，返回对应的Responder
+// 会关闭响应Body
+func StreamTo(resp *http.Response, w io.Writer) (Responder, error) {
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.New("error with code " + resp.Status)
+	}
+	ct := resp.Header.Get("Content-Type")
+	if strings.Contains(ct, "json") || strings.HasPrefix(ct, "text/") {
+		body, e := readBody(resp.Body)
+		if e != nil {
+			return nil, e
+		}
+		return JSONResponse(body), nil
+	}
+	_, e := io.Copy(w, resp.Body)
+	return nil, e
+}
+
 // SaveTo ...
 func SaveTo(response Responder, path string) error {
 	var err error
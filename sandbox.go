@@ -13,6 +13,7 @@ type Sandbox struct {
 	*SandboxProperty
 	subMchID string
 	subAppID string
+	cacheScope
 }
 
 // NewSandbox ...
@@ -34,9 +35,9 @@ func (obj *Sandbox) parse(options ...SandboxOption) {
 	}
 }
 
-func (obj *Sandbox) getCacheKey() string {
+func (obj *Sandbox) getCacheKey(scope *cacheScope) string {
 	name := strings.Join([]string{obj.AppID, obj.MchID}, ".")
-	return "webox.payment.sandbox." + fmt.Sprintf("%x", md5.Sum([]byte(name)))
+	return scope.cacheKey("payment.sandbox", fmt.Sprintf("%x", md5.Sum([]byte(name))))
 }
 
 // SignKey ...
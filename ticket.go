@@ -1,16 +1,31 @@
 package webox
 
 import (
+	"context"
+	"time"
 	"webox/api"
 	"webox/util"
 )
 
+/*TicketDefaultExpiresIn 未返回有效期时ticket的默认有效期(秒) */
+const TicketDefaultExpiresIn = 7200
+
 // TicketRes ticket response data
 type TicketRes struct {
 	ErrCode   int    `json:"errcode"`
 	ErrMsg    string `json:"errmsg"`
 	Ticket    string `json:"ticket"`
 	ExpiresIn int64  `json:"expires_in"`
+	// Expiry 根据ExpiresIn计算的过期时间，缓存时记录
+	Expiry time.Time `json:"expiry,omitempty"`
+}
+
+// Remaining 剩余有效期，已过期或未知时为0
+func (tr *TicketRes) Remaining() time.Duration {
+	if tr.Expiry.IsZero() {
+		return 0
+	}
+	return max(time.Until(tr.Expiry), 0)
 }
 
 /*Ticket Ticket */
@@ -30,15 +45,23 @@ func NewTicket(AccessToken *AccessToken) *Ticket {
 // https://api.weixin.qq.com/cgi-bin/ticket/getticket?access_token=ACCESS_TOKEN&type=wx_card
 // type: jsapi,wx_card
 func (t *Ticket) Get(s string) Responder {
+	return t.get(context.Background(), s)
+}
+
+func (t *Ticket) get(ctx context.Context, s string) Responder {
 
-	p := t.KeyMap().Set("type", s)
-	return GetTicket(p)
+	client := NewClient(ClientAccessToken(t.AccessToken))
+	return client.Get(ctx, util.URL(api.ApiWeixin, api.GetTicket), util.Map{"type": s})
 }
 
 // GetTicketRes ...
 func (t *Ticket) GetTicketRes(s string) (*TicketRes, error) {
+	return t.getTicketRes(context.Background(), s)
+}
+
+func (t *Ticket) getTicketRes(ctx context.Context, s string) (*TicketRes, error) {
 	var tr TicketRes
-	ticket := t.Get(s)
+	ticket := t.get(ctx, s)
 
 	if err := ticket.Error(); err != nil {
 		return nil, err
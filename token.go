@@ -34,6 +34,10 @@ type Token struct {
 	// mechanisms for that TokenSource will not be used.
 	ExpiresIn int64 `json:"expires_in"`
 
+	// Expiry is the absolute expiration time computed from ExpiresIn
+	// when the token was received.
+	Expiry time.Time `json:"expiry,omitempty"`
+
 	// wechat openid
 	OpenID string `json:"openid"`
 
@@ -54,15 +58,29 @@ func (t *Token) KeyMap() util.Map {
 	}
 }
 
-/*SetExpiresIn set expires time */
+/*SetExpiresIn set absolute expires time, ExpiresIn is updated to the seconds from now */
 func (t *Token) SetExpiresIn(ti time.Time) *Token {
-	t.ExpiresIn = ti.Unix()
+	t.Expiry = ti
+	t.ExpiresIn = int64(time.Until(ti) / time.Second)
 	return t
 }
 
-/*GetExpiresIn get expires time */
+/*GetExpiresIn get absolute expires time, zero when unknown */
 func (t *Token) GetExpiresIn() time.Time {
-	return time.Unix(t.ExpiresIn, 0)
+	return t.Expiry
+}
+
+/*Remaining get remaining lifetime, zero when expired or unknown */
+func (t *Token) Remaining() time.Duration {
+	if t.Expiry.IsZero() {
+		return 0
+	}
+	return max(time.Until(t.Expiry), 0)
+}
+
+/*Expired check whether the token is expired, token without expiry never expires */
+func (t *Token) Expired() bool {
+	return !t.Expiry.IsZero() && !time.Now().Before(t.Expiry)
 }
 
 /*GetScopes get AccessToken scopes for get AccessToken*/
@@ -72,7 +90,7 @@ func (t *Token) GetScopes() []string {
 
 /*SetScopes set AccessToken scopes for get AccessToken*/
 func (t *Token) SetScopes(s []string) *Token {
-	strings.Join(s, ",")
+	t.Scope = strings.Join(s, ",")
 	return t
 }
 
@@ -0,0 +1,174 @@
+package webox
+
+import (
+	"context"
+	"crypto/md5"
+	"errors"
+	"fmt"
+	"strconv"
+	"webox/api"
+	"webox/util"
+)
+
+/*TokenProvider access_token的来源，由AccessToken负责缓存、单飞及加锁 */
+type TokenProvider interface {
+	// Key 标识token的来源，不同来源的token分别缓存
+	Key() string
+	// RequestToken 请求token，forceRefresh为true时表示当前token已失效，须返回新token
+	RequestToken(ctx context.Context, forceRefresh bool) (*Token, error)
+}
+
+/*ClassicTokenProvider 通过GET /cgi-bin/token获取token，每次获取都会使之前的token在5分钟后失效 */
+type ClassicTokenProvider struct {
+	URL      string
+	Property *AccessTokenProperty
+}
+
+// NewClassicTokenProvider url为空时使用默认的接口地址
+func NewClassicTokenProvider(url string, property *AccessTokenProperty) *ClassicTokenProvider {
+	return &ClassicTokenProvider{
+		URL:      util.MustString(url, util.URL(api.ApiWeixin, api.AccessToken)),
+		Property: property,
+	}
+}
+
+// Key 包含secret的摘要，AppID相同而secret不同的配置不会共用token
+func (obj *ClassicTokenProvider) Key() string {
+	return "classic." + credentialsKey(obj.Property)
+}
+
+// RequestToken ...
+func (obj *ClassicTokenProvider) RequestToken(ctx context.Context, forceRefresh bool) (*Token, error) {
+	return doTokenRequest(ctx, &RequestContent{
+		Method: api.GET,
+		URL:    obj.URL,
+		Query:  obj.Property.ToMap(),
+		Body:   buildBody(nil, BodyTypeNone),
+	})
+}
+
+/*
+StableTokenProvider 通过POST /cgi-bin/stable_token获取稳定版token
+普通模式下有效期内重复获取返回相同的token，forceRefresh时使用强制刷新模式
+*/
+type StableTokenProvider struct {
+	URL      string
+	Property *AccessTokenProperty
+}
+
+// NewStableTokenProvider url为空时使用默认的接口地址
+func NewStableTokenProvider(url string, property *AccessTokenProperty) *StableTokenProvider {
+	return &StableTokenProvider{
+		URL:      util.MustString(url, util.URL(api.ApiWeixin, api.StableAccessToken)),
+		Property: property,
+	}
+}
+
+// Key 包含secret的摘要，AppID相同而secret不同的配置不会共用token
+func (obj *StableTokenProvider) Key() string {
+	return "stable." + credentialsKey(obj.Property)
+}
+
+// RequestToken ...
+// HTTP请求方式: POST
+// https://api.weixin.qq.com/cgi-bin/stable_token
+func (obj *StableTokenProvider) RequestToken(ctx context.Context, forceRefresh bool) (*Token, error) {
+	return doTokenRequest(ctx, &RequestContent{
+		Method: api.POST,
+		URL:    obj.URL,
+		Body: buildBody(util.Map{
+			"grant_type":    util.MustString(obj.Property.GrantType, GrantTypeClient),
+			"appid":         obj.Property.AppID,
+			"secret":        obj.Property.AppSecret,
+			"force_refresh": forceRefresh,
+		}, BodyTypeJSON),
+	})
+}
+
+/*
+ServerTokenProvider 从统一的中控服务获取token
+以GET请求URL，参数为appid及force_refresh，返回与/cgi-bin/token相同格式的JSON
+*/
+type ServerTokenProvider struct {
+	URL   string
+	AppID string
+	Query util.Map // 附加的请求参数，如中控服务的鉴权信息
+}
+
+// NewServerTokenProvider ...
+func NewServerTokenProvider(url, appID string, options ...ServerTokenProviderOption) *ServerTokenProvider {
+	provider := &ServerTokenProvider{
+		URL:   url,
+		AppID: appID,
+	}
+	for _, o := range options {
+		o(provider)
+	}
+	return provider
+}
+
+// Key ...
+func (obj *ServerTokenProvider) Key() string {
+	return "server." + obj.URL + "." + obj.AppID
+}
+
+// RequestToken ...
+func (obj *ServerTokenProvider) RequestToken(ctx context.Context, forceRefresh bool) (*Token, error) {
+	query := util.CombineMaps(util.Map{
+		"appid":         obj.AppID,
+		"force_refresh": strconv.FormatBool(forceRefresh),
+	}, obj.Query)
+	return doTokenRequest(ctx, &RequestContent{
+		Method: api.GET,
+		URL:    obj.URL,
+		Query:  query,
+		Body:   buildBody(nil, BodyTypeNone),
+	})
+}
+
+/*StaticTokenProvider 始终返回固定的token，用于测试 */
+type StaticTokenProvider struct {
+	Token *Token
+}
+
+// NewStaticTokenProvider ...
+func NewStaticTokenProvider(token string) *StaticTokenProvider {
+	return &StaticTokenProvider{
+		Token: &Token{AccessToken: token},
+	}
+}
+
+// Key 未设置Token时为空token的摘要，RequestToken会返回错误
+func (obj *StaticTokenProvider) Key() string {
+	var token string
+	if obj.Token != nil {
+		token = obj.Token.AccessToken
+	}
+	c := md5.Sum([]byte(token))
+	return fmt.Sprintf("static.%x", c[:])
+}
+
+// RequestToken ...
+func (obj *StaticTokenProvider) RequestToken(ctx context.Context, forceRefresh bool) (*Token, error) {
+	if obj.Token == nil || obj.Token.AccessToken == "" {
+		return nil, errors.New("empty static token")
+	}
+	token := *obj.Token
+	return &token, nil
+}
+
+// doTokenRequest 获取token的请求本身不携带access_token
+func doTokenRequest(ctx context.Context, content *RequestContent) (*Token, error) {
+	var t Token
+	resp := NewClient().do(ctx, content)
+	if e := resp.Error(); e != nil {
+		return nil, e
+	}
+	if e := resp.Unmarshal(&t); e != nil {
+		return nil, e
+	}
+	if t.AccessToken == "" {
+		return nil, errors.New("empty access_token in response")
+	}
+	return &t, nil
+}
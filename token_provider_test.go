@@ -0,0 +1,104 @@
+package webox
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"webox/api"
+	"webox/util"
+)
+
+// TestStableTokenProvider_RequestToken ...
+func TestStableTokenProvider_RequestToken(t *testing.T) {
+	var bodies []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		if r.Method != http.MethodPost || r.URL.Path != api.StableAccessToken {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		bodies = append(bodies, string(b))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"access_token":"stable","expires_in":7200}`))
+	}))
+	defer srv.Close()
+
+	provider := NewStableTokenProvider(srv.URL+api.StableAccessToken, &AccessTokenProperty{AppID: "wx", AppSecret: "secret"})
+	for _, force := range []bool{false, true} {
+		token, e := provider.RequestToken(context.Background(), force)
+		if e != nil {
+			t.Fatal(e)
+		}
+		if token.AccessToken != "stable" || token.ExpiresIn != 7200 {
+			t.Fatalf("unexpected token %+v", token)
+		}
+	}
+	if len(bodies) != 2 || !strings.Contains(bodies[0], `"force_refresh":false`) || !strings.Contains(bodies[1], `"force_refresh":true`) ||
+		!strings.Contains(bodies[0], `"grant_type":"client_credential"`) {
+		t.Fatalf("unexpected request bodies %v", bodies)
+	}
+}
+
+// TestServerTokenProvider_Error 中控服务返回的错误码应作为错误返回
+func TestServerTokenProvider_Error(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("appid") != "wx" || r.URL.Query().Get("force_refresh") != "true" {
+			_, _ = w.Write([]byte(`{"errcode":40013,"errmsg":"invalid appid"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"access_token":"central","expires_in":3600}`))
+	}))
+	defer srv.Close()
+
+	if _, e := NewServerTokenProvider(srv.URL, "wx1").RequestToken(context.Background(), true); e == nil {
+		t.Fatal("expected error for invalid appid")
+	}
+	token, e := NewServerTokenProvider(srv.URL, "wx").RequestToken(context.Background(), true)
+	if e != nil || token.AccessToken != "central" {
+		t.Fatalf("unexpected token %+v, %v", token, e)
+	}
+}
+
+// TestServerTokenProviderQuery 附加参数随请求发送
+func TestServerTokenProviderQuery(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("auth") != "key" {
+			_, _ = w.Write([]byte(`{"errcode":40001,"errmsg":"unauthorized"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"access_token":"central","expires_in":3600}`))
+	}))
+	defer srv.Close()
+
+	provider := NewServerTokenProvider(srv.URL, "wx", ServerTokenProviderQuery(util.Map{"auth": "key"}))
+	if token, e := provider.RequestToken(context.Background(), false); e != nil || token.AccessToken != "central" {
+		t.Fatalf("unexpected token %+v, %v", token, e)
+	}
+}
+
+// TestTokenProvider_Key secret不同的配置使用不同的key，零值StaticTokenProvider不会panic
+func TestTokenProvider_Key(t *testing.T) {
+	secret1 := &AccessTokenProperty{AppID: "wx", AppSecret: "secret1"}
+	secret2 := &AccessTokenProperty{AppID: "wx", AppSecret: "secret2"}
+	if NewClassicTokenProvider("", secret1).Key() == NewClassicTokenProvider("", secret2).Key() {
+		t.Fatal("classic providers with different secrets share a key")
+	}
+	if NewStableTokenProvider("", secret1).Key() == NewStableTokenProvider("", secret2).Key() {
+		t.Fatal("stable providers with different secrets share a key")
+	}
+	if strings.Contains(NewClassicTokenProvider("", secret1).Key(), "secret1") {
+		t.Fatal("key should not contain the secret")
+	}
+
+	provider := &StaticTokenProvider{}
+	if provider.Key() == "" {
+		t.Fatal("expected a key for an empty static provider")
+	}
+	if _, e := provider.RequestToken(context.Background(), false); e == nil {
+		t.Fatal("expected error for an empty static provider")
+	}
+}
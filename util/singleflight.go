@@ -0,0 +1,68 @@
+package util
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+/*SingleFlight 合并同一key的并发调用，执行期间到达的调用等待并共享同一结果 */
+type SingleFlight struct {
+	mu    sync.Mutex
+	calls map[string]*flightCall
+}
+
+type flightCall struct {
+	done chan struct{}
+	val  any
+	err  error
+}
+
+// Do 执行fn，shared表示结果是否与其他调用共享
+// fn panic时等待中的调用得到错误，panic在执行fn的调用中重新抛出
+func (g *SingleFlight) Do(key string, fn func() (any, error)) (v any, e error, shared bool) {
+	return g.DoContext(context.Background(), key, fn)
+}
+
+// DoContext 与Do相同，等待其他调用的结果时ctx结束则返回ctx的错误，不影响正在执行的fn
+func (g *SingleFlight) DoContext(ctx context.Context, key string, fn func() (any, error)) (v any, e error, shared bool) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = make(map[string]*flightCall)
+	}
+	if c, b := g.calls[key]; b {
+		g.mu.Unlock()
+		select {
+		case <-c.done:
+			return c.val, c.err, true
+		case <-ctx.Done():
+			return nil, ctx.Err(), true
+		}
+	}
+	c := &flightCall{done: make(chan struct{})}
+	g.calls[key] = c
+	g.mu.Unlock()
+
+	defer func() {
+		g.mu.Lock()
+		delete(g.calls, key)
+		g.mu.Unlock()
+		close(c.done)
+	}()
+	//fn未正常返回（panic或runtime.Goexit）时等待中的调用得到该错误
+	c.err = fmt.Errorf("single flight %q: call did not return", key)
+	var panicked any
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				panicked = r
+				c.err = fmt.Errorf("single flight %q panicked: %v", key, r)
+			}
+		}()
+		c.val, c.err = fn()
+	}()
+	if panicked != nil {
+		panic(panicked)
+	}
+	return c.val, c.err, false
+}
@@ -0,0 +1,46 @@
+package util
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestSingleFlight_Panic fn panic时执行者重新panic，等待者得到错误
+func TestSingleFlight_Panic(t *testing.T) {
+	var g SingleFlight
+	started := make(chan struct{})
+
+	var wg sync.WaitGroup
+	var waiterVal any
+	var waiterErr error
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		<-started
+		waiterVal, waiterErr, _ = g.Do("key", func() (any, error) { return "unexpected", nil })
+	}()
+
+	func() {
+		defer func() {
+			if r := recover(); r != "boom" {
+				t.Errorf("got panic %v, want boom", r)
+			}
+		}()
+		_, _, _ = g.Do("key", func() (any, error) {
+			close(started)
+			// 等待者进入等待后再panic
+			time.Sleep(50 * time.Millisecond)
+			panic("boom")
+		})
+	}()
+	wg.Wait()
+
+	if waiterVal != nil || waiterErr == nil || !strings.Contains(waiterErr.Error(), "boom") {
+		t.Fatalf("waiter got %v %v, want panic error", waiterVal, waiterErr)
+	}
+	if v, e, _ := g.Do("key", func() (any, error) { return "ok", nil }); v != "ok" || e != nil {
+		t.Fatalf("got %v %v after panic", v, e)
+	}
+}